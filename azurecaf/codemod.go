@@ -0,0 +1,361 @@
+package azurecaf
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Rewrite modes reported by RewriteLiteralNames.
+const (
+	CodemodModePassthrough = "passthrough"
+	CodemodModeDecomposed  = "decomposed"
+)
+
+// CodemodOptions controls how RewriteLiteralNames expresses hard-coded names.
+type CodemodOptions struct {
+	// Decompose tries to express each name as prefixes, name and suffixes around the
+	// resource slug. Names that cannot be decomposed fall back to passthrough.
+	Decompose bool
+}
+
+// CodemodRewrite describes a resource whose literal name was (or could not be) replaced.
+type CodemodRewrite struct {
+	Address    string
+	Name       string
+	DataSource string
+	Mode       string
+	Reason     string
+}
+
+// CodemodResult holds the outcome of rewriting a single Terraform file.
+type CodemodResult struct {
+	Filename  string
+	Original  []byte
+	Rewritten []byte
+	Rewrites  []CodemodRewrite
+	Skipped   []CodemodRewrite
+}
+
+// Changed reports whether the file content was modified.
+func (r *CodemodResult) Changed() bool {
+	return string(r.Original) != string(r.Rewritten)
+}
+
+// Diff returns a unified diff between the original and the rewritten file.
+func (r *CodemodResult) Diff() string {
+	return unifiedDiff(r.Filename, r.Original, r.Rewritten)
+}
+
+type codemodEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// RewriteLiteralNames finds azurerm resources covered by ResourceDefinitions whose
+// name argument is a string literal, adds a data "azurecaf_name" block in front of
+// each of them and points the name argument at the data source result.
+//
+// Edits are spliced into the original source so comments and formatting of the
+// rest of the file are preserved. Names that the data source would not reproduce
+// exactly (non-compliant names for instance) are reported as skipped.
+func RewriteLiteralNames(filename string, src []byte, options CodemodOptions) (*CodemodResult, error) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("unable to parse %s: %s", filename, diags.Error())
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("unable to parse %s: unexpected body type", filename)
+	}
+
+	result := &CodemodResult{Filename: filename, Original: src}
+	edits := []codemodEdit{}
+	for _, block := range body.Blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 {
+			continue
+		}
+		resourceType := block.Labels[0]
+		if _, found := ResourceDefinitions[resourceType]; !found {
+			continue
+		}
+		attribute, found := block.Body.Attributes["name"]
+		if !found {
+			continue
+		}
+		template, ok := attribute.Expr.(*hclsyntax.TemplateExpr)
+		if !ok || !template.IsStringLiteral() {
+			continue
+		}
+		value, valueDiags := template.Value(nil)
+		if valueDiags.HasErrors() {
+			continue
+		}
+
+		name := value.AsString()
+		label := strings.TrimPrefix(resourceType, "azurerm_") + "_" + block.Labels[1]
+		rewrite := CodemodRewrite{
+			Address:    resourceType + "." + block.Labels[1],
+			Name:       name,
+			DataSource: "data.azurecaf_name." + label,
+		}
+
+		arguments, mode, err := codemodArguments(resourceType, name, options)
+		if err != nil {
+			rewrite.Reason = err.Error()
+			result.Skipped = append(result.Skipped, rewrite)
+			continue
+		}
+		rewrite.Mode = mode
+
+		lineStart := leadingCommentStart(src, block.TypeRange.Start.Byte-(block.TypeRange.Start.Column-1))
+		edits = append(edits,
+			codemodEdit{start: lineStart, end: lineStart, text: renderNameDataBlock(label, resourceType, arguments)},
+			codemodEdit{start: attribute.Expr.Range().Start.Byte, end: attribute.Expr.Range().End.Byte, text: rewrite.DataSource + ".result"},
+		)
+		result.Rewrites = append(result.Rewrites, rewrite)
+	}
+
+	// Apply the edits from the end of the file so earlier offsets stay valid
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+	rewritten := string(src)
+	for _, edit := range edits {
+		rewritten = rewritten[:edit.start] + edit.text + rewritten[edit.end:]
+	}
+	result.Rewritten = []byte(rewritten)
+	return result, nil
+}
+
+// codemodArgument is a single argument of the generated data source, kept in order.
+type codemodArgument struct {
+	name  string
+	value cty.Value
+}
+
+// codemodArguments returns the arguments of a data "azurecaf_name" block that
+// reproduces name exactly, preferring a decomposition when requested.
+func codemodArguments(resourceType string, name string, options CodemodOptions) ([]codemodArgument, string, error) {
+	namePrecedence := []string{"name", "slug", "random", "suffixes", "prefixes"}
+
+	if options.Decompose {
+		for _, separator := range []string{"-", "_", "."} {
+			components, err := parseName(name, resourceType, separator, 0)
+			if err != nil || components.Confidence != NameConfidenceHigh {
+				continue
+			}
+			arguments := []codemodArgument{
+				{"name", cty.StringVal(components.Name)},
+				{"resource_type", cty.StringVal(resourceType)},
+			}
+			if len(components.Prefixes) > 0 {
+				arguments = append(arguments, codemodArgument{"prefixes", stringListValue(components.Prefixes)})
+			}
+			if len(components.Suffixes) > 0 {
+				arguments = append(arguments, codemodArgument{"suffixes", stringListValue(components.Suffixes)})
+			}
+			if components.Separator != "-" {
+				arguments = append(arguments, codemodArgument{"separator", cty.StringVal(components.Separator)})
+			}
+			return arguments, CodemodModeDecomposed, nil
+		}
+	}
+
+	generated, err := getResourceName(resourceType, "-", nil, name, nil, "", ConventionCafClassic, true, true, true, namePrecedence)
+	if err != nil {
+		return nil, "", err
+	}
+	if generated != name {
+		return nil, "", fmt.Errorf("passthrough would change the name '%s' into '%s'", name, generated)
+	}
+	return []codemodArgument{
+		{"name", cty.StringVal(name)},
+		{"resource_type", cty.StringVal(resourceType)},
+		{"passthrough", cty.True},
+	}, CodemodModePassthrough, nil
+}
+
+// leadingCommentStart moves offset, the start of a line, up over the comment lines
+// directly above it so that inserted blocks do not split a resource from its comment.
+func leadingCommentStart(src []byte, offset int) int {
+	for offset > 0 {
+		previous := strings.LastIndexByte(string(src[:offset-1]), '\n') + 1
+		line := strings.TrimSpace(string(src[previous : offset-1]))
+		if !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "//") {
+			break
+		}
+		offset = previous
+	}
+	return offset
+}
+
+func stringListValue(values []string) cty.Value {
+	items := make([]cty.Value, len(values))
+	for i, value := range values {
+		items[i] = cty.StringVal(value)
+	}
+	return cty.ListVal(items)
+}
+
+func renderNameDataBlock(label string, resourceType string, arguments []codemodArgument) string {
+	file := hclwrite.NewEmptyFile()
+	block := file.Body().AppendNewBlock("data", []string{"azurecaf_name", label})
+	for _, argument := range arguments {
+		block.Body().SetAttributeValue(argument.name, argument.value)
+	}
+	return string(hclwrite.Format(file.Bytes())) + "\n"
+}
+
+// diffOp is a single line of an edit script: ' ' (kept), '-' (removed) or '+' (added).
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff renders the difference between two versions of a file as a unified
+// diff with three lines of context.
+func unifiedDiff(path string, before []byte, after []byte) string {
+	if string(before) == string(after) {
+		return ""
+	}
+	ops := myersDiff(splitLines(string(before)), splitLines(string(after)))
+
+	// Line numbers (0-based) in each version at the start of every op
+	aLines := make([]int, len(ops)+1)
+	bLines := make([]int, len(ops)+1)
+	for i, op := range ops {
+		aLines[i+1], bLines[i+1] = aLines[i], bLines[i]
+		if op.kind != '+' {
+			aLines[i+1]++
+		}
+		if op.kind != '-' {
+			bLines[i+1]++
+		}
+	}
+
+	const context = 3
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", path, path)
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		lastChange := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				lastChange = j
+			} else if j-lastChange > 2*context {
+				break
+			}
+		}
+		end := lastChange + context + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		aStart, aCount := aLines[start]+1, aLines[end]-aLines[start]
+		bStart, bCount := bLines[start]+1, bLines[end]-bLines[start]
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+		for _, op := range ops[start:end] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.text)
+			out.WriteByte('\n')
+		}
+		i = end
+	}
+	return out.String()
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// myersDiff computes a shortest edit script between two sequences of lines
+// using the greedy algorithm described by Eugene W. Myers.
+func myersDiff(a []string, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	trace := [][]int{}
+
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the trace backwards to recover the edit script
+	ops := []diffOp{}
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var previousK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			previousK = k + 1
+		} else {
+			previousK = k - 1
+		}
+		previousX := v[offset+previousK]
+		previousY := previousX - previousK
+		for x > previousX && y > previousY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == previousX {
+				ops = append(ops, diffOp{'+', b[y-1]})
+				y--
+			} else {
+				ops = append(ops, diffOp{'-', a[x-1]})
+				x--
+			}
+		}
+	}
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
@@ -0,0 +1,124 @@
+package azurecaf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+const testCodemodConfig = `# Storage for the application
+resource "azurerm_storage_account" "main" {
+  name                     = "stmyapp001"
+  resource_group_name      = azurerm_resource_group.main.name
+  account_tier             = "Standard"
+}
+
+resource "azurerm_key_vault" "main" {
+  name = "kv-prd-myapp-001"
+}
+
+resource "azurerm_resource_group" "computed" {
+  name = "rg-${var.environment}"
+}
+
+resource "azurerm_storage_account" "invalid" {
+  name = "Not-A-Valid-Storage-Account"
+}
+
+resource "azurerm_not_covered" "other" {
+  name = "untouched"
+}
+`
+
+func TestRewriteLiteralNames_Passthrough(t *testing.T) {
+	result, err := RewriteLiteralNames("main.tf", []byte(testCodemodConfig), CodemodOptions{})
+	if err != nil {
+		t.Fatalf("RewriteLiteralNames failed: %v", err)
+	}
+
+	if len(result.Rewrites) != 2 {
+		t.Fatalf("Expected 2 rewrites, got %+v", result.Rewrites)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Address != "azurerm_storage_account.invalid" {
+		t.Errorf("Expected the invalid storage account to be skipped, got %+v", result.Skipped)
+	}
+
+	rewritten := string(result.Rewritten)
+	for _, expected := range []string{
+		`data "azurecaf_name" "storage_account_main" {`,
+		`name          = "stmyapp001"`,
+		`passthrough   = true`,
+		`name                     = data.azurecaf_name.storage_account_main.result`,
+		`name = data.azurecaf_name.key_vault_main.result`,
+		`name = "rg-${var.environment}"`,
+		`name = "untouched"`,
+		"}\n\n# Storage for the application\nresource \"azurerm_storage_account\" \"main\"",
+	} {
+		if !strings.Contains(rewritten, expected) {
+			t.Errorf("Expected rewritten file to contain %q:\n%s", expected, rewritten)
+		}
+	}
+
+	// The rewritten configuration must still be valid HCL
+	if _, diags := hclsyntax.ParseConfig(result.Rewritten, "main.tf", hcl.InitialPos); diags.HasErrors() {
+		t.Errorf("Rewritten configuration is not valid HCL: %s", diags.Error())
+	}
+}
+
+func TestRewriteLiteralNames_Decompose(t *testing.T) {
+	result, err := RewriteLiteralNames("main.tf", []byte(testCodemodConfig), CodemodOptions{Decompose: true})
+	if err != nil {
+		t.Fatalf("RewriteLiteralNames failed: %v", err)
+	}
+
+	modes := map[string]string{}
+	for _, rewrite := range result.Rewrites {
+		modes[rewrite.Address] = rewrite.Mode
+	}
+	// Storage account names have no separator to split on
+	if modes["azurerm_storage_account.main"] != CodemodModePassthrough {
+		t.Errorf("Expected storage account to fall back to passthrough, got %s", modes["azurerm_storage_account.main"])
+	}
+	if modes["azurerm_key_vault.main"] != CodemodModeDecomposed {
+		t.Errorf("Expected key vault to be decomposed, got %s", modes["azurerm_key_vault.main"])
+	}
+	if !strings.Contains(string(result.Rewritten), `suffixes      = ["myapp", "001"]`) {
+		t.Errorf("Expected decomposed suffixes in:\n%s", result.Rewritten)
+	}
+}
+
+func TestRewriteLiteralNames_InvalidHCL(t *testing.T) {
+	if _, err := RewriteLiteralNames("main.tf", []byte(`resource "azurerm_key_vault" {`), CodemodOptions{}); err == nil {
+		t.Error("Expected an error for invalid HCL")
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	after := "a\nb\nc\nd\nE\nf\ng\nh\ni\nj\nk\n"
+	diff := unifiedDiff("file.tf", []byte(before), []byte(after))
+	expected := `--- a/file.tf
++++ b/file.tf
+@@ -2,9 +2,10 @@
+ b
+ c
+ d
+-e
++E
+ f
+ g
+ h
+ i
+ j
++k
+`
+	if diff != expected {
+		t.Errorf("Unexpected diff:\n%s\nexpected:\n%s", diff, expected)
+	}
+
+	if unifiedDiff("file.tf", []byte(before), []byte(before)) != "" {
+		t.Error("Expected no diff for identical content")
+	}
+}
@@ -152,7 +152,7 @@ func TestGetNameReadResult(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			rd := schema.TestResourceDataRaw(t, dataName().Schema, tc.resourceData)
-			err := getNameReadResult(rd, nil)
+			_, err := getNameReadResult(rd, nil)
 
 			if tc.expectedErr && err == nil {
 				t.Error("Expected error but got none")
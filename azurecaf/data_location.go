@@ -0,0 +1,104 @@
+package azurecaf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataLocation creates and returns the schema for the azurecaf_location data source.
+//
+// This data source looks up an Azure region in LocationDefinitions, generated from
+// locationDefinition.json, and returns its standard short code, paired region and
+// geography, so that every configuration abbreviates regions the same way.
+func dataLocation() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataLocationRead,
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateLocation,
+				Description:  "Name (westeurope), display name (West Europe) or short code (weu) of the region.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the region.",
+			},
+			"display_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Display name of the region.",
+			},
+			"short_code": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Standard short code of the region used in names.",
+			},
+			"paired_region": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the paired region, empty when the region has none.",
+			},
+			"paired_region_short_code": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Short code of the paired region, empty when the region has none.",
+			},
+			"geography": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Geography of the region.",
+			},
+		},
+	}
+}
+
+func dataLocationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	location, err := getLocation(d.Get("location").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(location.Name)
+	d.Set("name", location.Name)
+	d.Set("display_name", location.DisplayName)
+	d.Set("short_code", location.ShortCode)
+	d.Set("paired_region", location.PairedRegion)
+	d.Set("paired_region_short_code", "")
+	if paired, found := LocationDefinitions[location.PairedRegion]; found {
+		d.Set("paired_region_short_code", paired.ShortCode)
+	}
+	d.Set("geography", location.Geography)
+	return nil
+}
+
+// getLocation returns the region of LocationDefinitions matching value, a region name,
+// a display name or a short code, without case.
+func getLocation(value string) (*LocationStructure, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(value, " ", ""))
+	if location, found := LocationDefinitions[normalized]; found {
+		return &location, nil
+	}
+	for _, location := range LocationDefinitions {
+		if location.ShortCode == normalized {
+			return &location, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid location %s", value)
+}
+
+func validateLocation(i interface{}, k string) ([]string, []error) {
+	value, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := getLocation(value); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", k, err)}
+	}
+	return nil, nil
+}
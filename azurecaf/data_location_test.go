@@ -0,0 +1,95 @@
+package azurecaf
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestLocationDefinitions(t *testing.T) {
+	shortCode := regexp.MustCompile("^[a-z][a-z0-9]{1,4}$")
+	shortCodes := map[string]string{}
+	for name, location := range LocationDefinitions {
+		if location.Name != name || location.DisplayName == "" || location.Geography == "" {
+			t.Errorf("Incomplete definition of %s: %+v", name, location)
+		}
+		if !shortCode.MatchString(location.ShortCode) {
+			t.Errorf("Short code %s of %s is not a lowercase code of 2 to 5 characters", location.ShortCode, name)
+		}
+		if other, found := shortCodes[location.ShortCode]; found {
+			t.Errorf("Short code %s of %s is already used by %s", location.ShortCode, name, other)
+		}
+		shortCodes[location.ShortCode] = name
+		if _, found := LocationDefinitions[location.PairedRegion]; location.PairedRegion != "" && !found {
+			t.Errorf("Paired region %s of %s is not defined", location.PairedRegion, name)
+		}
+	}
+}
+
+func TestDataLocation(t *testing.T) {
+	for _, value := range []string{"westeurope", "West Europe", "WEU"} {
+		t.Run(value, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, dataLocation().Schema, map[string]interface{}{"location": value})
+			if diags := dataLocationRead(context.Background(), d, nil); diags.HasError() {
+				t.Fatalf("dataLocationRead failed: %v", diags)
+			}
+			expected := map[string]string{
+				"name":                     "westeurope",
+				"display_name":             "West Europe",
+				"short_code":               "weu",
+				"paired_region":            "northeurope",
+				"paired_region_short_code": "neu",
+				"geography":                "Europe",
+			}
+			for key, value := range expected {
+				if d.Get(key).(string) != value {
+					t.Errorf("Expected %s for %s, got %s", value, key, d.Get(key).(string))
+				}
+			}
+			if d.Id() != "westeurope" {
+				t.Errorf("Expected the region name as ID, got %s", d.Id())
+			}
+		})
+	}
+
+	if _, errs := validateLocation("westmars", "location"); len(errs) == 0 {
+		t.Error("Expected an unknown location to be rejected")
+	}
+	d := schema.TestResourceDataRaw(t, dataLocation().Schema, map[string]interface{}{"location": "polandcentral"})
+	if diags := dataLocationRead(context.Background(), d, nil); diags.HasError() || d.Get("paired_region_short_code").(string) != "" {
+		t.Errorf("Expected no paired region for polandcentral, got %s (%v)", d.Get("paired_region").(string), diags)
+	}
+}
+
+func TestNameLocation(t *testing.T) {
+	result, results, err := generateNames(nameParameters{
+		Name:          "app",
+		Suffixes:      []string{"001"},
+		Separator:     "-",
+		ResourceType:  "azurerm_resource_group",
+		ResourceTypes: []string{"azurerm_storage_account"},
+		CleanInput:    true,
+		UseSlug:       true,
+		Location:      "East US 2",
+	})
+	if err != nil {
+		t.Fatalf("generateNames failed: %v", err)
+	}
+	if result != "rg-app-eus2-001" || results["azurerm_storage_account"] != "stappeus2001" {
+		t.Errorf("Expected the short code before the suffixes, got %s and %s", result, results["azurerm_storage_account"])
+	}
+
+	data := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{
+		"name":          "app",
+		"resource_type": "azurerm_key_vault",
+		"location":      "northeurope",
+	})
+	if diags := dataNameRead(context.Background(), data, nil); diags.HasError() {
+		t.Fatalf("dataNameRead failed: %v", diags)
+	}
+	if result := data.Get("result").(string); result != "kv-app-neu" {
+		t.Errorf("Expected kv-app-neu, got %s", result)
+	}
+}
@@ -2,6 +2,8 @@ package azurecaf
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -20,8 +22,8 @@ import (
 //   - Better for single resource name generation
 //   - Integrates naturally with Terraform's data flow
 //
-// Use the resource version when you need to generate multiple related names
-// using the resource_types parameter.
+// Use the resource version when the random part of the names must be kept in the
+// state, for instance to compose them again in place with keepers.
 func dataName() *schema.Resource {
 	resourceMapsKeys := make([]string, 0, len(ResourceDefinitions))
 	for k := range ResourceDefinitions {
@@ -90,6 +92,23 @@ func dataName() *schema.Resource {
 				ValidateFunc: validation.StringInSlice(resourceMapsKeys, false),
 				ForceNew:     true,
 			},
+			// Additional resource types, their names share the random part of result
+			"resource_types": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(resourceMapsKeys, false),
+				},
+				Optional: true,
+				ForceNew: true,
+			},
+			"results": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
 			"random_seed": {
 				Type:     schema.TypeInt,
 				Optional: true,
@@ -101,39 +120,199 @@ func dataName() *schema.Resource {
 				ForceNew: true,
 				Default:  true,
 			},
+			// Built-in convention or named convention defined in the provider configuration
+			"convention": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			// Replacement of a separator the resource type does not allow
+			"separator_fallback": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{SeparatorFallbackNone, SeparatorFallbackAuto}, false),
+			},
+			// Separator used for the resource type
+			"separators": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			// Region whose short code is added before the suffixes
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateLocation,
+			},
+			// Instance numbers ending the names of results_list
+			"instance_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"instance_start": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultInstanceStart,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"instance_padding": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultInstancePadding,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 10),
+			},
+			// Names of resource_type of each instance, in order and by instance number
+			"results_list": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"instances": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			// Replace the words of the components with the environment dictionary, and with
+			// the abbreviation dictionary when the name is too long
+			"abbreviate": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			// Words replaced by an abbreviation
+			"abbreviations": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			// Hash added to the name when the resource type has a global scope
+			"uniqueness": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{UniquenessNone, UniquenessAuto}, false),
+			},
+			// Added to the input of the uniqueness hash, e.g. a subscription ID
+			"uniqueness_seed": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"scope": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// Scope of each resource type
+			"scopes": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			// Key of the scope of the name, e.g. the name of its resource group, used to
+			// detect the names generated more than once during a run
+			"scope_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 		},
 	}
 }
 
 func dataNameRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	getNameReadResult(d, meta)
-	return diag.Diagnostics{}
+	diags, err := getNameReadResult(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
 }
 
-func getNameReadResult(d *schema.ResourceData, meta interface{}) error {
-	name := d.Get("name").(string)
-	prefixes := convertInterfaceToString(d.Get("prefixes").([]interface{}))
-	suffixes := convertInterfaceToString(d.Get("suffixes").([]interface{}))
-	separator := d.Get("separator").(string)
-	resourceType := d.Get("resource_type").(string)
-	cleanInput := d.Get("clean_input").(bool)
-	passthrough := d.Get("passthrough").(bool)
-	useSlug := d.Get("use_slug").(bool)
-	randomLength := d.Get("random_length").(int)
-	randomSeed := int64(d.Get("random_seed").(int))
-
-	convention := ConventionCafClassic
-
-	randomSuffix := randSeq(int(randomLength), &randomSeed)
+// getNameReadResult composes the names of the azurecaf_name data source and sets them
+// in d. The returned warnings report the names generated more than once during the run.
+func getNameReadResult(d *schema.ResourceData, meta interface{}) (diag.Diagnostics, error) {
+	params, err := readNameParameters(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	params.Replacements = map[string]string{}
 
-	namePrecedence := []string{"name", "slug", "random", "suffixes", "prefixes"}
+	if len(params.ResourceTypes) > 0 {
+		if _, err := validateResourceType(params.ResourceType, params.ResourceTypes); err != nil {
+			return nil, err
+		}
+	}
 
-	resourceName, err := getResourceName(resourceType, separator, prefixes, name, suffixes, randomSuffix, convention, cleanInput, passthrough, useSlug, namePrecedence)
+	// The names of resource_types and the instances share the random part of the name
+	params.RandomString = randSeq(params.randomPartLength(), &params.RandomSeed)
+	compose := nameComposer(params)
+	// Without resource_type, result keeps the general name only when no resource_types
+	// are named, the names of resource_types are in results
+	var resourceName string
+	if params.ResourceType != "" || len(params.ResourceTypes) == 0 {
+		if resourceName, err = compose(params.ResourceType); err != nil {
+			return nil, err
+		}
+	}
+	results := make(map[string]string, len(params.ResourceTypes))
+	for _, resourceTypeName := range params.ResourceTypes {
+		if results[resourceTypeName], err = compose(resourceTypeName); err != nil {
+			return nil, err
+		}
+	}
+	instanceNames, err := generateInstanceNames(params)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	names := map[string]string{}
+	if resourceName != "" {
+		names[params.ResourceType] = resourceName
+	}
+	for resourceTypeName, name := range results {
+		names[resourceTypeName] = name
+	}
+	// The data source registers its names under its ID, so that reading it again is
+	// not a duplicate, while another configuration generating the same names is
+	id := dataNameID(names)
+	diags, err := registerNames(meta, "data.azurecaf_name."+id, d.Get("scope_key").(string), nameResults(params, resourceName, results, instanceNames))
+	if err != nil {
+		return nil, err
 	}
 	d.Set("result", resourceName)
+	d.Set("results", results)
+	d.Set("results_list", instanceNames)
+	d.Set("instances", nameInstances(params, instanceNames))
+	d.Set("separators", nameSeparators(params))
+	d.Set("abbreviations", params.Replacements)
+	setNameScopes(d, params)
 
-	d.SetId(resourceName)
-	return nil
+	d.SetId(id)
+	return diags, nil
+}
+
+// dataNameID returns the ID of an azurecaf_name data source, a hash of its names by
+// resource type: the same name can be generated for several resource types.
+func dataNameID(names map[string]string) string {
+	hash := sha256.New()
+	for _, resourceTypeName := range sortedKeys(names) {
+		fmt.Fprintf(hash, "%s=%s\n", resourceTypeName, names[resourceTypeName])
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
 }
@@ -0,0 +1,103 @@
+package azurecaf
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataNameComponents creates and returns the schema for the azurecaf_name_components data source.
+//
+// This data source decomposes an existing name into the components the azurecaf_name
+// resource is built from: prefixes, slug, base name, random part and suffixes. It is
+// meant to help re-expressing hand-written or imported names in the non-passthrough
+// form. The confidence attribute tells how reliable the decomposition is.
+func dataNameComponents() *schema.Resource {
+	resourceMapsKeys := make([]string, 0, len(ResourceDefinitions))
+	for k := range ResourceDefinitions {
+		resourceMapsKeys = append(resourceMapsKeys, k)
+	}
+
+	return &schema.Resource{
+		ReadContext: dataNameComponentsRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Existing name to decompose.",
+			},
+			"resource_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(resourceMapsKeys, false),
+				Description:  "Resource type the name was generated for.",
+			},
+			"separator": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "-",
+				Description: "Separator used between the name components.",
+			},
+			"random_length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Length of the random part to look for after the base name.",
+			},
+			"prefixes": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+			"slug": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"base_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"random_string": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"suffixes": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+			},
+			"use_slug": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"confidence": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Confidence of the decomposition: high, medium, low or none.",
+			},
+		},
+	}
+}
+
+func dataNameComponentsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	resourceType := d.Get("resource_type").(string)
+
+	parsed, err := parseName(name, resourceType, d.Get("separator").(string), d.Get("random_length").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("prefixes", parsed.Prefixes)
+	d.Set("slug", parsed.Slug)
+	d.Set("base_name", parsed.Name)
+	d.Set("random_string", parsed.Random)
+	d.Set("suffixes", parsed.Suffixes)
+	d.Set("use_slug", parsed.UseSlug)
+	d.Set("confidence", parsed.Confidence)
+	d.SetId(resourceType + ":" + name)
+	return nil
+}
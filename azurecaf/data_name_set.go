@@ -0,0 +1,32 @@
+package azurecaf
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataNameSet creates and returns the schema for the azurecaf_name_set data source,
+// which generates the names of a name set during the plan.
+func dataNameSet() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataNameSetRead,
+		Schema:      nameSetSchema(),
+	}
+}
+
+func dataNameSetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags, err := getNameSetResult(d, meta, "")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	results := d.Get("results").(map[string]interface{})
+	names := make([]string, 0, len(results))
+	for _, resourceTypeName := range convertInterfaceToString(d.Get("resource_types").([]interface{})) {
+		names = append(names, results[resourceTypeName].(string))
+	}
+	d.SetId(strings.Join(names, ","))
+	return diags
+}
@@ -0,0 +1,83 @@
+package azurecaf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataNameResourceTypes(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{
+		"name":           "app",
+		"resource_type":  "azurerm_resource_group",
+		"resource_types": []interface{}{"azurerm_key_vault", "azurerm_storage_account"},
+		"random_length":  4,
+	})
+	if diags := dataNameRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("dataNameRead failed: %v", diags)
+	}
+	result := d.Get("result").(string)
+	random := strings.TrimPrefix(result, "rg-app-")
+	results := d.Get("results").(map[string]interface{})
+	if len(random) != 4 || results["azurerm_key_vault"] != "kv-app-"+random || results["azurerm_storage_account"] != "stapp"+random {
+		t.Errorf("Expected names sharing the random part of %s, got %v", result, results)
+	}
+	if scopes := d.Get("scopes").(map[string]interface{}); scopes["azurerm_key_vault"] != ScopeGlobal {
+		t.Errorf("Expected the scope of each resource type, got %v", scopes)
+	}
+	if d.Id() == result {
+		t.Errorf("Expected an ID other than the name, got %s", d.Id())
+	}
+
+	// Only resource_types
+	d = schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{
+		"name":           "app",
+		"resource_types": []interface{}{"azurerm_key_vault"},
+	})
+	if diags := dataNameRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("dataNameRead failed: %v", diags)
+	}
+	if results := d.Get("results").(map[string]interface{}); results["azurerm_key_vault"] != "kv-app" {
+		t.Errorf("Expected kv-app, got %v", results)
+	}
+	if result := d.Get("result").(string); result != "" {
+		t.Errorf("Expected no result without resource_type, got %s", result)
+	}
+
+	// The same name for another resource type has another ID
+	first := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{"name": "app", "resource_type": "azurerm_resource_group", "use_slug": false})
+	second := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{"name": "app", "resource_type": "azurerm_virtual_network", "use_slug": false})
+	dataNameRead(context.Background(), first, nil)
+	dataNameRead(context.Background(), second, nil)
+	if first.Get("result") != second.Get("result") || first.Id() == second.Id() {
+		t.Errorf("Expected the same name with different IDs, got %s (%s) and %s (%s)", first.Get("result"), first.Id(), second.Get("result"), second.Id())
+	}
+
+	if _, err := getNameReadResult(schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{
+		"name":           "app",
+		"resource_types": []interface{}{"azurerm_key_vault", "azurerm_unknown"},
+	}), nil); err == nil || !strings.Contains(err.Error(), "invalid resource type azurerm_unknown") {
+		t.Errorf("Expected an invalid resource type to be rejected, got %v", err)
+	}
+}
+
+func TestDataNameResourceTypes_Duplicates(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"on_duplicate_name": DuplicateNameError})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("providerConfigure failed: %v", diags)
+	}
+
+	// Without resource_type, no general name is registered
+	for _, resourceTypeName := range []string{"azurerm_key_vault", "azurerm_storage_account"} {
+		data := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{
+			"name":           "app",
+			"resource_types": []interface{}{resourceTypeName},
+		})
+		if _, err := getNameReadResult(data, meta); err != nil {
+			t.Errorf("getNameReadResult failed for %s: %v", resourceTypeName, err)
+		}
+	}
+}
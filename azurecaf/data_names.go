@@ -0,0 +1,223 @@
+package azurecaf
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataNames creates and returns the schema for the azurecaf_names data source.
+//
+// This data source generates many names in one read, from a spec block per key,
+// instead of one azurecaf_name data source per name. Every spec is composed even when
+// others fail, so that a single plan reports the errors of every key.
+func dataNames() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataNamesRead,
+		Schema: map[string]*schema.Schema{
+			// spec is a list of blocks with a key rather than a map: the values of a
+			// map can only be strings, numbers or booleans, not blocks. A set would
+			// show the change of one spec as the removal and addition of a block.
+			"spec": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: nameSpecSchema(),
+				},
+			},
+			// Report the errors in errors instead of failing
+			"fail_on_error": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  true,
+			},
+			"results": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			// Names of the specs with instance_count, by key and instance number
+			"instances": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"errors": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+		},
+	}
+}
+
+// nameSpecSchema returns the schema of a spec of azurecaf_names: the arguments of the
+// azurecaf_name data source, but resource_types, and the key of the name. Each spec
+// names one resource type, which is required.
+func nameSpecSchema() map[string]*schema.Schema {
+	specSchema := map[string]*schema.Schema{
+		// Key of the name in results and errors
+		"key": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.NoZeroValues,
+		},
+	}
+	for key, attribute := range dataName().Schema {
+		if !attribute.Optional || key == "resource_types" {
+			continue
+		}
+		argument := *attribute
+		argument.ForceNew = false
+		specSchema[key] = &argument
+	}
+	specSchema["resource_type"].Optional = false
+	specSchema["resource_type"].Required = true
+	return specSchema
+}
+
+func dataNamesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	batch := generateNameBatch(d.Get("spec").([]interface{}), meta)
+
+	if len(batch.Errors) > 0 && d.Get("fail_on_error").(bool) {
+		var diags diag.Diagnostics
+		for _, key := range sortedKeys(batch.Errors) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Unable to generate the name %s", key),
+				Detail:   batch.Errors[key],
+			})
+		}
+		return diags
+	}
+
+	hash := sha256.New()
+	for _, key := range sortedKeys(batch.Results) {
+		fmt.Fprintf(hash, "%s=%s\n", key, batch.Results[key])
+	}
+	for _, key := range sortedKeys(batch.Instances) {
+		fmt.Fprintf(hash, "%s=%s\n", key, batch.Instances[key])
+	}
+	for _, key := range sortedKeys(batch.Errors) {
+		fmt.Fprintf(hash, "%s!%s\n", key, batch.Errors[key])
+	}
+	d.SetId(fmt.Sprintf("%x", hash.Sum(nil)))
+	d.Set("results", batch.Results)
+	d.Set("instances", batch.Instances)
+	d.Set("errors", batch.Errors)
+	return batch.Warnings
+}
+
+// nameBatch holds the names of the specs of azurecaf_names.
+type nameBatch struct {
+	// Results are the names by key.
+	Results map[string]string
+	// Instances are the instance names by key and instance number, e.g. vm/001.
+	Instances map[string]string
+	// Errors are the errors of the specs that failed by key.
+	Errors map[string]string
+	// Warnings report the names generated more than once during the run.
+	Warnings diag.Diagnostics
+}
+
+// generateNameBatch returns the names of each spec by key, and the error of each spec
+// that failed by key. The definition of each resource type, with its regular
+// expressions, is checked once before any name is composed. The names are registered
+// by key order, so that two specs generating the same name are reported.
+func generateNameBatch(specs []interface{}, meta interface{}) nameBatch {
+	batch := nameBatch{Results: map[string]string{}, Instances: map[string]string{}, Errors: map[string]string{}}
+
+	compileErrors := map[string]error{}
+	for _, raw := range specs {
+		resourceTypeName := raw.(map[string]interface{})["resource_type"].(string)
+		if _, compiled := compileErrors[resourceTypeName]; compiled {
+			continue
+		}
+		_, compileErrors[resourceTypeName] = getResource(resourceTypeName)
+	}
+
+	specsByKey := map[string]map[string]interface{}{}
+	specCounts := map[string]int{}
+	keys := []string{}
+	for _, raw := range specs {
+		spec := raw.(map[string]interface{})
+		key := spec["key"].(string)
+		if specCounts[key] == 0 {
+			keys = append(keys, key)
+		}
+		specsByKey[key] = spec
+		specCounts[key]++
+	}
+	sort.Strings(keys)
+
+	argumentSchema := dataName().Schema
+	for _, key := range keys {
+		if specCounts[key] > 1 {
+			batch.Errors[key] = fmt.Sprintf("the key is used by %d specs", specCounts[key])
+			continue
+		}
+		spec := specsByKey[key]
+		params, name, instanceNames, err := generateBatchName(spec, argumentSchema, meta, compileErrors[spec["resource_type"].(string)])
+		if err == nil {
+			var warnings diag.Diagnostics
+			warnings, err = registerNames(meta, "", spec["scope_key"].(string), nameResults(params, name, nil, instanceNames))
+			for _, warning := range warnings {
+				warning.Detail = fmt.Sprintf("spec %s: %s", key, warning.Detail)
+				batch.Warnings = append(batch.Warnings, warning)
+			}
+		}
+		if err != nil {
+			batch.Errors[key] = err.Error()
+			continue
+		}
+		batch.Results[key] = name
+		for instance, instanceName := range nameInstances(params, instanceNames) {
+			batch.Instances[key+"/"+instance] = instanceName
+		}
+	}
+	return batch
+}
+
+// generateBatchName returns the parameters, the name and the instance names of one
+// spec of azurecaf_names. The spec is read with argumentSchema, the schema of the
+// azurecaf_name data source, the arguments it lacks take their default.
+func generateBatchName(spec map[string]interface{}, argumentSchema map[string]*schema.Schema, meta interface{}, compileErr error) (nameParameters, string, []string, error) {
+	if compileErr != nil {
+		return nameParameters{}, "", nil, compileErr
+	}
+	params, err := readNameParameters(rawNameState{values: spec, schema: argumentSchema}, meta)
+	if err != nil {
+		return params, "", nil, err
+	}
+	// The instances share the random part of the name
+	params.RandomString = randSeq(params.randomPartLength(), &params.RandomSeed)
+	result, _, err := generateNames(params)
+	if err != nil {
+		return params, "", nil, err
+	}
+	instanceNames, err := generateInstanceNames(params)
+	return params, result, instanceNames, err
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,150 @@
+package azurecaf
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testNameSpec(key string, resourceType string, name string) map[string]interface{} {
+	return map[string]interface{}{"key": key, "resource_type": resourceType, "name": name}
+}
+
+func TestDataNames(t *testing.T) {
+	storage := testNameSpec("data", "azurerm_storage_account", "data")
+	storage["suffixes"] = []interface{}{"001"}
+	d := schema.TestResourceDataRaw(t, dataNames().Schema, map[string]interface{}{
+		"spec": []interface{}{
+			testNameSpec("rg", "azurerm_resource_group", "app"),
+			testNameSpec("kv", "azurerm_key_vault", "app"),
+			storage,
+		},
+	})
+	if diags := dataNamesRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("dataNamesRead failed: %v", diags)
+	}
+	expected := map[string]string{"rg": "rg-app", "kv": "kv-app", "data": "stdata001"}
+	results := d.Get("results").(map[string]interface{})
+	for key, name := range expected {
+		if results[key] != name {
+			t.Errorf("Expected %s for %s, got %v", name, key, results[key])
+		}
+	}
+	if d.Id() == "" || len(d.Get("errors").(map[string]interface{})) != 0 {
+		t.Errorf("Expected an ID and no errors, got %s and %v", d.Id(), d.Get("errors"))
+	}
+}
+
+func TestDataNames_Errors(t *testing.T) {
+	random := testNameSpec("vm", "azurerm_windows_virtual_machine", "app")
+	random["random_length"] = 20
+	convention := testNameSpec("kv", "azurerm_key_vault", "app")
+	convention["convention"] = "missing"
+	config := map[string]interface{}{
+		"spec": []interface{}{
+			random,
+			convention,
+			testNameSpec("rg", "azurerm_resource_group", "app"),
+			testNameSpec("dup", "azurerm_resource_group", "one"),
+			testNameSpec("dup", "azurerm_resource_group", "two"),
+		},
+	}
+
+	// Every failing key is reported at once
+	diags := dataNamesRead(context.Background(), schema.TestResourceDataRaw(t, dataNames().Schema, config), nil)
+	if len(diags) != 3 {
+		t.Fatalf("Expected 3 errors, got %v", diags)
+	}
+	for i, key := range []string{"dup", "kv", "vm"} {
+		if !strings.HasSuffix(diags[i].Summary, " "+key) {
+			t.Errorf("Expected the error of %s, got %s: %s", key, diags[i].Summary, diags[i].Detail)
+		}
+	}
+
+	config["fail_on_error"] = false
+	d := schema.TestResourceDataRaw(t, dataNames().Schema, config)
+	if diags := dataNamesRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("dataNamesRead failed: %v", diags)
+	}
+	results := d.Get("results").(map[string]interface{})
+	errs := d.Get("errors").(map[string]interface{})
+	if len(results) != 1 || results["rg"] != "rg-app" || len(errs) != 3 {
+		t.Errorf("Expected the names and the errors by key, got %v and %v", results, errs)
+	}
+	if !strings.Contains(errs["dup"].(string), "2 specs") || !strings.Contains(errs["kv"].(string), "convention missing") {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+}
+
+func TestDataNames_Options(t *testing.T) {
+	storage := testNameSpec("data", "azurerm_storage_account", "data")
+	storage["uniqueness"] = UniquenessAuto
+	vault := testNameSpec("kv", "azurerm_key_vault", "app")
+	vault["separator"] = "_"
+	vault["separator_fallback"] = SeparatorFallbackAuto
+	machines := testNameSpec("vm", "azurerm_linux_virtual_machine", "app")
+	machines["instance_count"] = 2
+	group := testNameSpec("rg", "azurerm_resource_group", "application")
+	group["convention"] = ConventionPassThrough
+	d := schema.TestResourceDataRaw(t, dataNames().Schema, map[string]interface{}{
+		"spec": []interface{}{storage, vault, machines, group},
+	})
+	if diags := dataNamesRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("dataNamesRead failed: %v", diags)
+	}
+
+	// Every option of the azurecaf_name data source is supported
+	results := d.Get("results").(map[string]interface{})
+	if data := results["data"].(string); !regexp.MustCompile(`^stdata[a-z]{5}$`).MatchString(data) {
+		t.Errorf("Expected a uniqueness hash, got %s", data)
+	}
+	if results["kv"] != "kvApp" {
+		t.Errorf("Expected the separator to fall back, got %v", results["kv"])
+	}
+	if results["rg"] != "application" {
+		t.Errorf("Expected the passthrough convention, got %v", results["rg"])
+	}
+	instances := d.Get("instances").(map[string]interface{})
+	if instances["vm/001"] != "vm-app-001" || instances["vm/002"] != "vm-app-002" || len(instances) != 2 {
+		t.Errorf("Expected the instances by key, got %v", instances)
+	}
+}
+
+func TestDataNames_Duplicates(t *testing.T) {
+	for _, onDuplicateName := range []string{DuplicateNameWarn, DuplicateNameError} {
+		t.Run(onDuplicateName, func(t *testing.T) {
+			p := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"on_duplicate_name": onDuplicateName})
+			meta, diags := providerConfigure(context.Background(), p)
+			if diags.HasError() {
+				t.Fatalf("providerConfigure failed: %v", diags)
+			}
+			d := schema.TestResourceDataRaw(t, dataNames().Schema, map[string]interface{}{
+				"spec": []interface{}{
+					testNameSpec("first", "azurerm_key_vault", "app"),
+					testNameSpec("second", "azurerm_key_vault", "app"),
+				},
+				"fail_on_error": false,
+			})
+			diags = dataNamesRead(context.Background(), d, meta)
+			if diags.HasError() {
+				t.Fatalf("dataNamesRead failed: %v", diags)
+			}
+
+			// Two specs of the batch generating the same name are reported
+			errs := d.Get("errors").(map[string]interface{})
+			if onDuplicateName == DuplicateNameError {
+				if len(diags) != 0 || len(errs) != 1 || !strings.Contains(errs["second"].(string), "name kv-app of azurerm_key_vault is generated more than once") {
+					t.Errorf("Expected the duplicate to be an error of second, got %v and %v", diags, errs)
+				}
+				return
+			}
+			if len(errs) != 0 || len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, "spec second: name kv-app") {
+				t.Errorf("Expected the duplicate to be a warning, got %v and %v", diags, errs)
+			}
+		})
+	}
+}
@@ -70,7 +70,7 @@ func TestResourceNameInvalidInputs(t *testing.T) {
 			rd := schema.TestResourceDataRaw(t, resourceName().Schema, tc.resourceData)
 
 			// Test the validation
-			err := getNameResult(rd, nil)
+			_, err := getNameResult(rd, nil)
 
 			if tc.expectedError && err == nil {
 				t.Errorf("%s: Expected error but got none", tc.description)
@@ -152,7 +152,7 @@ func TestBoundaryConditions(t *testing.T) {
 			}
 
 			rd := schema.TestResourceDataRaw(t, resourceName().Schema, resourceData)
-			err := getNameResult(rd, nil)
+			_, err := getNameResult(rd, nil)
 
 			// Log the result for debugging
 			if err != nil {
@@ -194,7 +194,7 @@ func TestValidResourceTypes(t *testing.T) {
 			}
 
 			rd := schema.TestResourceDataRaw(t, resourceName().Schema, resourceData)
-			err := getNameResult(rd, nil)
+			_, err := getNameResult(rd, nil)
 
 			if err != nil {
 				t.Errorf("Valid resource type %s should not fail: %v", resourceType, err)
@@ -229,7 +229,7 @@ func TestNamingConventions(t *testing.T) {
 			}
 
 			rd := schema.TestResourceDataRaw(t, resourceName().Schema, resourceData)
-			err := getNameResult(rd, nil)
+			_, err := getNameResult(rd, nil)
 
 			if err != nil {
 				t.Errorf("Convention %s failed: %v", convention, err)
@@ -14,7 +14,7 @@ func TestGetNameResultExcessiveRandomLength(t *testing.T) {
 		"random_length": 25,                        // exceeds max length
 	})
 
-	err := getNameResult(rd, nil)
+	_, err := getNameResult(rd, nil)
 	if err == nil {
 		t.Error("Expected error for exceeding max length but got none")
 	}
@@ -1,6 +1,7 @@
 package azurecaf
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -31,11 +32,8 @@ func TestGetResourceNameValidationError(t *testing.T) {
 	}
 }
 
-// Test regex compilation error in getResult by handling panic (since there's a nil pointer issue)
+// Test regex compilation error in getResult
 func TestGetResultRegexError(t *testing.T) {
-	// Some code paths in getResult don't properly handle invalid regex and cause panics
-	// We can test this by using defer/recover
-
 	// Save the original resource
 	originalResource := Resources["st"]
 
@@ -47,11 +45,6 @@ func TestGetResultRegexError(t *testing.T) {
 	defer func() {
 		// Restore original after test
 		Resources["st"] = originalResource
-
-		// Recover from panic
-		if r := recover(); r == nil {
-			t.Error("Expected panic but none occurred")
-		}
 	}()
 
 	rd := schema.TestResourceDataRaw(t, resourceNamingConvention().Schema, map[string]interface{}{
@@ -60,8 +53,10 @@ func TestGetResultRegexError(t *testing.T) {
 		"convention":    "random",
 	})
 
-	// This will cause a panic when it hits the invalid regex
-	_ = getResult(rd, nil)
+	// The compile error is reported instead of a panic
+	if err := getResult(rd, nil); err == nil || !strings.Contains(err.Error(), "invalid cleaning regular expression") {
+		t.Errorf("Expected a regular expression error, got %v", err)
+	}
 }
 
 // Test getResult with validation regex error
@@ -77,11 +72,6 @@ func TestGetResultValidationRegexError(t *testing.T) {
 	defer func() {
 		// Restore original after test
 		Resources["st"] = originalResource
-
-		// Recover from panic
-		if r := recover(); r == nil {
-			t.Error("Expected panic but none occurred")
-		}
 	}()
 
 	rd := schema.TestResourceDataRaw(t, resourceNamingConvention().Schema, map[string]interface{}{
@@ -90,8 +80,22 @@ func TestGetResultValidationRegexError(t *testing.T) {
 		"convention":    "random",
 	})
 
-	// This will cause a panic when it hits the invalid validation regex
-	_ = getResult(rd, nil)
+	// The compile error is reported instead of a panic
+	if err := getResult(rd, nil); err == nil || !strings.Contains(err.Error(), "invalid validation regular expression") {
+		t.Errorf("Expected a regular expression error, got %v", err)
+	}
+
+	// The names of azurecaf_name report it too
+	originalDefinition := ResourceDefinitions["azurerm_storage_account"]
+	modifiedDefinition := originalDefinition
+	modifiedDefinition.ValidationRegExp = "["
+	ResourceDefinitions["azurerm_storage_account"] = modifiedDefinition
+	defer func() {
+		ResourceDefinitions["azurerm_storage_account"] = originalDefinition
+	}()
+	if _, err := getResourceName("azurerm_storage_account", "-", []string{}, "test", []string{}, "", "cafclassic", true, false, true, []string{"name"}); err == nil || !strings.Contains(err.Error(), "invalid validation regular expression of azurerm_storage_account") {
+		t.Errorf("Expected a regular expression error, got %v", err)
+	}
 }
 
 // Test getResult error handling with validation match failure
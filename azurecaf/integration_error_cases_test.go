@@ -1,6 +1,7 @@
 package azurecaf
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -53,12 +54,12 @@ func TestAcc_ErrorHandling(t *testing.T) {
 		})
 
 		// Try to create the resource - should fail validation
-		err := nameResource.Create(resourceData, nil)
-		if err == nil {
+		diags := nameResource.CreateContext(context.Background(), resourceData, nil)
+		if !diags.HasError() {
 			t.Error("Expected error for excessive random length, but got none")
 		}
-		if err != nil && !strings.Contains(err.Error(), "random_length") {
-			t.Errorf("Expected error about random_length, got: %v", err)
+		if diags.HasError() && !strings.Contains(diags[0].Summary, "random_length") {
+			t.Errorf("Expected error about random_length, got: %v", diags)
 		}
 	})
 
@@ -0,0 +1,190 @@
+package azurecaf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// jsonSchema is the subset of JSON Schema used by the documents published with the
+// provider: type, const, enum, properties, required, additionalProperties, items,
+// contains, uniqueItems, minItems, minLength, pattern and minimum.
+type jsonSchema struct {
+	Type                 string                 `json:"type"`
+	Const                interface{}            `json:"const"`
+	Enum                 []interface{}          `json:"enum"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Contains             *jsonSchema            `json:"contains"`
+	UniqueItems          bool                   `json:"uniqueItems"`
+	MinItems             *int                   `json:"minItems"`
+	MinLength            *int                   `json:"minLength"`
+	Pattern              string                 `json:"pattern"`
+	Minimum              *float64               `json:"minimum"`
+}
+
+func parseJSONSchema(document []byte) (*jsonSchema, error) {
+	var schema jsonSchema
+	if err := json.Unmarshal(document, &schema); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	return &schema, nil
+}
+
+// validate checks a value decoded by encoding/json against the schema and returns
+// one message per violation, prefixed with the JSON pointer of the invalid value.
+func (s *jsonSchema) validate(value interface{}) ([]string, error) {
+	errors := []string{}
+	err := s.validateAt("", value, &errors)
+	return errors, err
+}
+
+func (s *jsonSchema) validateAt(path string, value interface{}, errors *[]string) error {
+	location := path
+	if location == "" {
+		location = "/"
+	}
+	fail := func(format string, args ...interface{}) {
+		*errors = append(*errors, fmt.Sprintf("%s: %s", location, fmt.Sprintf(format, args...)))
+	}
+
+	if s.Type != "" && !jsonSchemaTypeMatches(s.Type, value) {
+		fail("expected %s", s.Type)
+		return nil
+	}
+	if s.Const != nil && !reflect.DeepEqual(s.Const, value) {
+		fail("must be %v", s.Const)
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, allowed := range s.Enum {
+			found = found || reflect.DeepEqual(allowed, value)
+		}
+		if !found {
+			fail("must be one of %v", s.Enum)
+		}
+	}
+
+	switch typed := value.(type) {
+	case string:
+		if s.MinLength != nil && len(typed) < *s.MinLength {
+			fail("must be at least %d characters long", *s.MinLength)
+		}
+		if s.Pattern != "" {
+			pattern, err := regexp.Compile(s.Pattern)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %s in JSON schema: %w", s.Pattern, err)
+			}
+			if !pattern.MatchString(typed) {
+				fail("must match %s", s.Pattern)
+			}
+		}
+	case float64:
+		if s.Minimum != nil && typed < *s.Minimum {
+			fail("must be at least %v", *s.Minimum)
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(typed) < *s.MinItems {
+			fail("must have at least %d items", *s.MinItems)
+		}
+		if s.UniqueItems {
+			for i := range typed {
+				for j := i + 1; j < len(typed); j++ {
+					if reflect.DeepEqual(typed[i], typed[j]) {
+						fail("items %d and %d are identical", i, j)
+					}
+				}
+			}
+		}
+		contained := false
+		for i, item := range typed {
+			if s.Items != nil {
+				if err := s.Items.validateAt(fmt.Sprintf("%s/%d", path, i), item, errors); err != nil {
+					return err
+				}
+			}
+			if s.Contains != nil && !contained {
+				itemErrors := []string{}
+				if err := s.Contains.validateAt("", item, &itemErrors); err != nil {
+					return err
+				}
+				contained = len(itemErrors) == 0
+			}
+		}
+		if s.Contains != nil && !contained {
+			fail("must contain an item matching the schema")
+		}
+	case map[string]interface{}:
+		for _, required := range s.Required {
+			if _, found := typed[required]; !found {
+				fail("missing required property %s", required)
+			}
+		}
+		additional, err := s.additionalProperties()
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			propertyPath := path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+			if property, found := s.Properties[key]; found {
+				if err := property.validateAt(propertyPath, typed[key], errors); err != nil {
+					return err
+				}
+				continue
+			}
+			switch {
+			case additional == nil:
+			case additional == jsonSchemaFalse:
+				fail("unknown property %s", key)
+			default:
+				if err := additional.validateAt(propertyPath, typed[key], errors); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// jsonSchemaFalse stands for "additionalProperties": false.
+var jsonSchemaFalse = &jsonSchema{}
+
+func (s *jsonSchema) additionalProperties() (*jsonSchema, error) {
+	switch raw := strings.TrimSpace(string(s.AdditionalProperties)); raw {
+	case "", "true":
+		return nil, nil
+	case "false":
+		return jsonSchemaFalse, nil
+	default:
+		return parseJSONSchema(s.AdditionalProperties)
+	}
+}
+
+func jsonSchemaTypeMatches(expected string, value interface{}) bool {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return expected == "object"
+	case []interface{}:
+		return expected == "array"
+	case string:
+		return expected == "string"
+	case bool:
+		return expected == "boolean"
+	case float64:
+		return expected == "number" || (expected == "integer" && typed == math.Trunc(typed))
+	case nil:
+		return expected == "null"
+	}
+	return false
+}
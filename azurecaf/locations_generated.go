@@ -0,0 +1,60 @@
+// Code generated by go generate; DO NOT EDIT.
+// This file was generated by robots using data from
+// locationDefinition.json
+
+package azurecaf
+
+// LocationDefinitions are a map of the Azure regions by name
+var LocationDefinitions = map[string]LocationStructure{
+	"australiacentral":   {"australiacentral", "Australia Central", "acl", "australiacentral2", "Australia"},
+	"australiacentral2":  {"australiacentral2", "Australia Central 2", "acl2", "australiacentral", "Australia"},
+	"australiaeast":      {"australiaeast", "Australia East", "ae", "australiasoutheast", "Australia"},
+	"australiasoutheast": {"australiasoutheast", "Australia Southeast", "ase", "australiaeast", "Australia"},
+	"brazilsouth":        {"brazilsouth", "Brazil South", "brs", "southcentralus", "Brazil"},
+	"brazilsoutheast":    {"brazilsoutheast", "Brazil Southeast", "brse", "brazilsouth", "Brazil"},
+	"canadacentral":      {"canadacentral", "Canada Central", "cac", "canadaeast", "Canada"},
+	"canadaeast":         {"canadaeast", "Canada East", "cae", "canadacentral", "Canada"},
+	"centralindia":       {"centralindia", "Central India", "inc", "southindia", "India"},
+	"centralus":          {"centralus", "Central US", "cus", "eastus2", "United States"},
+	"eastasia":           {"eastasia", "East Asia", "ea", "southeastasia", "Asia Pacific"},
+	"eastus":             {"eastus", "East US", "eus", "westus", "United States"},
+	"eastus2":            {"eastus2", "East US 2", "eus2", "centralus", "United States"},
+	"francecentral":      {"francecentral", "France Central", "frc", "francesouth", "France"},
+	"francesouth":        {"francesouth", "France South", "frs", "francecentral", "France"},
+	"germanynorth":       {"germanynorth", "Germany North", "gn", "germanywestcentral", "Germany"},
+	"germanywestcentral": {"germanywestcentral", "Germany West Central", "gwc", "germanynorth", "Germany"},
+	"israelcentral":      {"israelcentral", "Israel Central", "ilc", "", "Israel"},
+	"italynorth":         {"italynorth", "Italy North", "itn", "", "Italy"},
+	"japaneast":          {"japaneast", "Japan East", "jpe", "japanwest", "Japan"},
+	"japanwest":          {"japanwest", "Japan West", "jpw", "japaneast", "Japan"},
+	"koreacentral":       {"koreacentral", "Korea Central", "krc", "koreasouth", "Korea"},
+	"koreasouth":         {"koreasouth", "Korea South", "krs", "koreacentral", "Korea"},
+	"mexicocentral":      {"mexicocentral", "Mexico Central", "mxc", "", "Mexico"},
+	"newzealandnorth":    {"newzealandnorth", "New Zealand North", "nzn", "", "New Zealand"},
+	"northcentralus":     {"northcentralus", "North Central US", "ncus", "southcentralus", "United States"},
+	"northeurope":        {"northeurope", "North Europe", "neu", "westeurope", "Europe"},
+	"norwayeast":         {"norwayeast", "Norway East", "noe", "norwaywest", "Norway"},
+	"norwaywest":         {"norwaywest", "Norway West", "now", "norwayeast", "Norway"},
+	"polandcentral":      {"polandcentral", "Poland Central", "plc", "", "Poland"},
+	"qatarcentral":       {"qatarcentral", "Qatar Central", "qac", "", "Qatar"},
+	"southafricanorth":   {"southafricanorth", "South Africa North", "san", "southafricawest", "South Africa"},
+	"southafricawest":    {"southafricawest", "South Africa West", "saw", "southafricanorth", "South Africa"},
+	"southcentralus":     {"southcentralus", "South Central US", "scus", "northcentralus", "United States"},
+	"southeastasia":      {"southeastasia", "Southeast Asia", "sea", "eastasia", "Asia Pacific"},
+	"southindia":         {"southindia", "South India", "ins", "centralindia", "India"},
+	"spaincentral":       {"spaincentral", "Spain Central", "spc", "", "Spain"},
+	"swedencentral":      {"swedencentral", "Sweden Central", "sdc", "swedensouth", "Sweden"},
+	"swedensouth":        {"swedensouth", "Sweden South", "sds", "swedencentral", "Sweden"},
+	"switzerlandnorth":   {"switzerlandnorth", "Switzerland North", "chn", "switzerlandwest", "Switzerland"},
+	"switzerlandwest":    {"switzerlandwest", "Switzerland West", "chw", "switzerlandnorth", "Switzerland"},
+	"uaecentral":         {"uaecentral", "UAE Central", "uac", "uaenorth", "UAE"},
+	"uaenorth":           {"uaenorth", "UAE North", "uan", "uaecentral", "UAE"},
+	"uksouth":            {"uksouth", "UK South", "uks", "ukwest", "United Kingdom"},
+	"ukwest":             {"ukwest", "UK West", "ukw", "uksouth", "United Kingdom"},
+	"westcentralus":      {"westcentralus", "West Central US", "wcus", "westus2", "United States"},
+	"westeurope":         {"westeurope", "West Europe", "weu", "northeurope", "Europe"},
+	"westindia":          {"westindia", "West India", "inw", "southindia", "India"},
+	"westus":             {"westus", "West US", "wus", "eastus", "United States"},
+	"westus2":            {"westus2", "West US 2", "wus2", "westcentralus", "United States"},
+	"westus3":            {"westus3", "West US 3", "wus3", "eastus", "United States"},
+}
@@ -88,6 +88,20 @@ type ResourceStructure struct {
 	Scope string `json:"scope,omitempty"`
 }
 
+// LocationStructure stores the naming attributes of an Azure region
+type LocationStructure struct {
+	// Name of the region, e.g. westeurope
+	Name string `json:"name"`
+	// Display name of the region, e.g. West Europe
+	DisplayName string `json:"display_name"`
+	// Standard short code of the region used in names, e.g. weu
+	ShortCode string `json:"short_code"`
+	// Name of the paired region, empty when the region has none
+	PairedRegion string `json:"paired_region,omitempty"`
+	// Geography of the region, e.g. Europe
+	Geography string `json:"geography"`
+}
+
 var (
 	alphagenerator = []rune("abcdefghijklmnopqrstuvwxyz")
 )
@@ -98,17 +112,33 @@ func randSeq(length int, seed *int64) string {
 	if length <= 0 {
 		return ""
 	}
-	// initialize random seed
-	if seed == nil || *seed == 0 {
-		value := time.Now().UnixNano()
-		seed = &value
+	var value int64
+	if seed != nil {
+		value = *seed
+	}
+	return randSeqFrom(newRandomSource(value), length)
+}
+
+// newRandomSource returns the random source of a seed, or a time based source when
+// the seed is 0. rand.Seed has no effect since Go 1.24, a local source keeps seeded
+// values reproducible.
+func newRandomSource(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// randSeqFrom draws length random characters from random.
+func randSeqFrom(random *rand.Rand, length int) string {
+	if length <= 0 {
+		return ""
 	}
-	rand.Seed(*seed)
 	// generate at least one random character
 	b := make([]rune, length)
 	for i := range b {
 		// We need the random generated string to start with a letter
-		b[i] = alphagenerator[rand.Intn(len(alphagenerator)-1)]
+		b[i] = alphagenerator[random.Intn(len(alphagenerator)-1)]
 	}
 	return string(b)
 }
@@ -0,0 +1,156 @@
+package azurecaf
+
+import (
+	"strings"
+)
+
+// defaultEnvironments normalizes the names of environments. With abbreviate, the
+// words of the components are replaced before the components are cleaned.
+var defaultEnvironments = map[string]string{
+	"production":    "prd",
+	"prod":          "prd",
+	"preproduction": "ppd",
+	"preprod":       "ppd",
+	"staging":       "stg",
+	"stage":         "stg",
+	"testing":       "tst",
+	"test":          "tst",
+	"development":   "dev",
+	"develop":       "dev",
+	"sandbox":       "sbx",
+}
+
+// defaultAbbreviations shortens long words. With abbreviate, the words of the
+// components are replaced when the name is too long, before components are dropped.
+var defaultAbbreviations = map[string]string{
+	"application":    "app",
+	"applications":   "apps",
+	"backup":         "bkp",
+	"configuration":  "cfg",
+	"connectivity":   "conn",
+	"database":       "db",
+	"diagnostics":    "diag",
+	"environment":    "env",
+	"frontend":       "fe",
+	"backend":        "be",
+	"function":       "func",
+	"gateway":        "gw",
+	"infrastructure": "infra",
+	"management":     "mgmt",
+	"monitoring":     "mon",
+	"network":        "net",
+	"networking":     "net",
+	"operations":     "ops",
+	"platform":       "plt",
+	"primary":        "pri",
+	"security":       "sec",
+	"service":        "svc",
+	"services":       "svc",
+}
+
+// mergeDictionary returns the words of defaults replaced or completed by overrides,
+// matched without case. An empty abbreviation removes the word.
+func mergeDictionary(defaults map[string]string, overrides map[string]interface{}) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(overrides))
+	for word, abbreviation := range defaults {
+		merged[word] = abbreviation
+	}
+	for word, abbreviation := range overrides {
+		if abbreviation.(string) == "" {
+			delete(merged, strings.ToLower(word))
+			continue
+		}
+		merged[strings.ToLower(word)] = abbreviation.(string)
+	}
+	return merged
+}
+
+// abbreviateWords replaces each word of value found in dictionary. Words are delimited
+// by separators and matched without case. The replaced words are recorded in replaced,
+// when not nil.
+func abbreviateWords(value string, dictionary map[string]string, replaced map[string]string) string {
+	if len(dictionary) == 0 {
+		return value
+	}
+	var abbreviated strings.Builder
+	start := 0
+	flush := func(end int) {
+		word := value[start:end]
+		if abbreviation, found := dictionary[strings.ToLower(word)]; found && word != "" {
+			if replaced != nil && abbreviation != word {
+				replaced[word] = abbreviation
+			}
+			word = abbreviation
+		}
+		abbreviated.WriteString(word)
+	}
+	for i, r := range value {
+		if r == '-' || r == '_' || r == '.' || r == ' ' {
+			flush(i)
+			abbreviated.WriteRune(r)
+			start = i + 1
+		}
+	}
+	flush(len(value))
+	return abbreviated.String()
+}
+
+// abbreviateInputs replaces the words of the name, prefixes and suffixes of inputs.
+func abbreviateInputs(inputs nameInputs, dictionary map[string]string, replaced map[string]string) nameInputs {
+	inputs.Name = abbreviateWords(inputs.Name, dictionary, replaced)
+	prefixes := make([]string, len(inputs.Prefixes))
+	for i, prefix := range inputs.Prefixes {
+		prefixes[i] = abbreviateWords(prefix, dictionary, replaced)
+	}
+	suffixes := make([]string, len(inputs.Suffixes))
+	for i, suffix := range inputs.Suffixes {
+		suffixes[i] = abbreviateWords(suffix, dictionary, replaced)
+	}
+	inputs.Prefixes, inputs.Suffixes = prefixes, suffixes
+	return inputs
+}
+
+// composedLength returns the length of the name composed from every component of
+// inputs, before components are dropped to fit the resource.
+func composedLength(resource *ResourceStructure, inputs nameInputs, slug string, separator string, cleanInput bool, withRandom bool) (int, error) {
+	components := append(append(append([]string{}, inputs.Prefixes...), slug, inputs.Name), inputs.Suffixes...)
+	components = append(components, inputs.Hash, inputs.Instance)
+	if withRandom {
+		components = append(components, inputs.Random)
+	}
+	if cleanInput {
+		var err error
+		if components, err = cleanSlice(components, resource); err != nil {
+			return 0, err
+		}
+		if separator, err = cleanString(separator, resource); err != nil {
+			return 0, err
+		}
+	}
+	return len(concatenateParameters(separator, components)), nil
+}
+
+// dictionariesFromMeta returns the environment and abbreviation dictionaries of the
+// provider configuration, or the built-in ones. The abbreviations of the naming
+// policy, if any, are always replaced like the environments, and override them.
+func dictionariesFromMeta(meta interface{}) (map[string]string, map[string]string) {
+	config, ok := meta.(*providerConfiguration)
+	if !ok {
+		return defaultEnvironments, defaultAbbreviations
+	}
+	environments, abbreviations := defaultEnvironments, defaultAbbreviations
+	if config.Environments != nil {
+		environments, abbreviations = config.Environments, config.Abbreviations
+	}
+	if config.Policy == nil || len(config.Policy.Abbreviations) == 0 {
+		return environments, abbreviations
+	}
+	merged := make(map[string]string, len(environments)+len(config.Policy.Abbreviations))
+	for word, abbreviation := range environments {
+		merged[word] = abbreviation
+	}
+	for word, abbreviation := range config.Policy.Abbreviations {
+		merged[word] = abbreviation
+	}
+	return merged, abbreviations
+}
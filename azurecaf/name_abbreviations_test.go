@@ -0,0 +1,114 @@
+package azurecaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAbbreviateWords(t *testing.T) {
+	replaced := map[string]string{}
+	if value := abbreviateWords("Production-app_test", defaultEnvironments, replaced); value != "prd-app_tst" {
+		t.Errorf("Expected prd-app_tst, got %s", value)
+	}
+	if replaced["Production"] != "prd" || replaced["test"] != "tst" || len(replaced) != 2 {
+		t.Errorf("Expected the replaced words, got %v", replaced)
+	}
+	// Words are only matched whole
+	if value := abbreviateWords("productionapp", defaultEnvironments, nil); value != "productionapp" {
+		t.Errorf("Expected productionapp, got %s", value)
+	}
+
+	merged := mergeDictionary(defaultEnvironments, map[string]interface{}{"QA": "qa", "sandbox": ""})
+	if merged["qa"] != "qa" || merged["production"] != "prd" {
+		t.Errorf("Expected the overrides merged with the defaults, got %v", merged)
+	}
+	if _, found := merged["sandbox"]; found {
+		t.Error("Expected an empty abbreviation to remove the word")
+	}
+}
+
+func TestNameAbbreviate(t *testing.T) {
+	params := nameParameters{
+		Name:          "payments",
+		Prefixes:      []string{"production"},
+		Suffixes:      []string{"management", "001"},
+		Separator:     "-",
+		ResourceType:  "azurerm_resource_group",
+		ResourceTypes: []string{"azurerm_storage_account"},
+		CleanInput:    true,
+		UseSlug:       true,
+		Abbreviate:    true,
+		Environments:  defaultEnvironments,
+		Abbreviations: defaultAbbreviations,
+		Replacements:  map[string]string{},
+	}
+	result, results, err := generateNames(params)
+	if err != nil {
+		t.Fatalf("generateNames failed: %v", err)
+	}
+	// Management is only abbreviated where the name does not fit
+	if result != "prd-rg-payments-management-001" {
+		t.Errorf("Expected prd-rg-payments-management-001, got %s", result)
+	}
+	if results["azurerm_storage_account"] != "prdstpaymentsmgmt001" {
+		t.Errorf("Expected prdstpaymentsmgmt001, got %s", results["azurerm_storage_account"])
+	}
+	if params.Replacements["production"] != "prd" || params.Replacements["management"] != "mgmt" {
+		t.Errorf("Expected the replacements to be reported, got %v", params.Replacements)
+	}
+
+	params.Abbreviate = false
+	_, results, err = generateNames(params)
+	if err != nil {
+		t.Fatalf("generateNames failed: %v", err)
+	}
+	if results["azurerm_storage_account"] != "stpaymentsmanagement001" {
+		t.Errorf("Expected the prefix to be dropped without abbreviate, got %s", results["azurerm_storage_account"])
+	}
+}
+
+func TestNameAbbreviate_ProviderOverrides(t *testing.T) {
+	meta, diags := providerConfigure(context.Background(), schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"environments":  map[string]interface{}{"production": "p"},
+		"abbreviations": map[string]interface{}{"payments": "pay"},
+	}))
+	if diags.HasError() {
+		t.Fatalf("providerConfigure failed: %v", diags)
+	}
+
+	rd := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":          "payments-api",
+		"prefixes":      []interface{}{"production"},
+		"resource_type": "azurerm_storage_account",
+		"suffixes":      []interface{}{"management", "001"},
+		"abbreviate":    true,
+	})
+	if diags := resourceNameCreate(context.Background(), rd, meta); diags.HasError() {
+		t.Fatalf("resourceNameCreate failed: %v", diags)
+	}
+	if result := rd.Get("result").(string); result != "pstpayapimgmt001" {
+		t.Errorf("Expected pstpayapimgmt001, got %s", result)
+	}
+	expected := map[string]string{"production": "p", "payments": "pay", "management": "mgmt"}
+	abbreviations := rd.Get("abbreviations").(map[string]interface{})
+	for word, abbreviation := range expected {
+		if abbreviations[word] != abbreviation {
+			t.Errorf("Expected %s to be reported as %s, got %v", word, abbreviation, abbreviations)
+		}
+	}
+
+	data := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{
+		"name":          "app",
+		"prefixes":      []interface{}{"Development"},
+		"resource_type": "azurerm_key_vault",
+		"abbreviate":    true,
+	})
+	if diags := dataNameRead(context.Background(), data, nil); diags.HasError() {
+		t.Fatalf("dataNameRead failed: %v", diags)
+	}
+	if result := data.Get("result").(string); result != "dev-kv-app" {
+		t.Errorf("Expected dev-kv-app, got %s", result)
+	}
+}
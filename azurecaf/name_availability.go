@@ -0,0 +1,160 @@
+package azurecaf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// nameAvailabilityChecker checks that generated names are not taken, for instance by
+// a resource of another tenant. getNameResult calls it once the names are generated
+// and draws a new random part when a name is not available.
+type nameAvailabilityChecker interface {
+	// CheckNameAvailability reports whether name is available for resourceTypeName.
+	// Resource types the checker does not cover are reported available.
+	CheckNameAvailability(ctx context.Context, resourceTypeName string, name string) (*nameAvailability, error)
+}
+
+// nameAvailability is the result of a check, shaped like the response of the Azure
+// checkNameAvailability operations.
+type nameAvailability struct {
+	NameAvailable bool   `json:"nameAvailable"`
+	Reason        string `json:"reason,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+// nameAvailabilityEndpoint is the checkNameAvailability operation of a resource provider.
+type nameAvailabilityEndpoint struct {
+	Provider   string
+	APIVersion string
+	// Type is sent with the name, when the operation expects it.
+	Type string
+}
+
+// nameAvailabilityEndpoints are the checkNameAvailability operations by resource type.
+var nameAvailabilityEndpoints = map[string]nameAvailabilityEndpoint{
+	"azurerm_storage_account":      {"Microsoft.Storage", "2023-01-01", "Microsoft.Storage/storageAccounts"},
+	"azurerm_key_vault":            {"Microsoft.KeyVault", "2022-07-01", "Microsoft.KeyVault/vaults"},
+	"azurerm_container_registry":   {"Microsoft.ContainerRegistry", "2023-07-01", "Microsoft.ContainerRegistry/registries"},
+	"azurerm_servicebus_namespace": {"Microsoft.ServiceBus", "2021-11-01", ""},
+	"azurerm_eventhub_namespace":   {"Microsoft.EventHub", "2024-01-01", ""},
+	"azurerm_api_management":       {"Microsoft.ApiManagement", "2022-08-01", ""},
+	"azurerm_app_service":          {"Microsoft.Web", "2022-09-01", "Microsoft.Web/sites"},
+	"azurerm_linux_web_app":        {"Microsoft.Web", "2022-09-01", "Microsoft.Web/sites"},
+	"azurerm_windows_web_app":      {"Microsoft.Web", "2022-09-01", "Microsoft.Web/sites"},
+	"azurerm_function_app":         {"Microsoft.Web", "2022-09-01", "Microsoft.Web/sites"},
+}
+
+// httpNameAvailabilityChecker calls the checkNameAvailability operations of the
+// resource providers of a subscription, under BaseURL, e.g. https://management.azure.com.
+type httpNameAvailabilityChecker struct {
+	BaseURL        string
+	SubscriptionID string
+	Tokens         tokenSource
+	Client         *http.Client
+}
+
+func (c *httpNameAvailabilityChecker) CheckNameAvailability(ctx context.Context, resourceTypeName string, name string) (*nameAvailability, error) {
+	endpoint, found := nameAvailabilityEndpoints[resourceTypeName]
+	if !found {
+		return &nameAvailability{NameAvailable: true}, nil
+	}
+
+	body := map[string]string{"name": name}
+	if endpoint.Type != "" {
+		body["type"] = endpoint.Type
+	}
+	content, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/subscriptions/%s/providers/%s/checkNameAvailability?api-version=%s", strings.TrimSuffix(c.BaseURL, "/"), c.SubscriptionID, endpoint.Provider, endpoint.APIVersion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Tokens != nil {
+		token, err := c.Tokens.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get a token to check the availability of %s: %w", name, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check the availability of %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unable to check the availability of %s: %s %s, the token may have expired or may not be valid for subscription %s", name, resp.Status, strings.TrimSpace(string(detail)), c.SubscriptionID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unable to check the availability of %s: %s %s", name, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	var availability nameAvailability
+	if err := json.NewDecoder(resp.Body).Decode(&availability); err != nil {
+		return nil, fmt.Errorf("unable to decode the availability of %s: %w", name, err)
+	}
+	return &availability, nil
+}
+
+// nameUnavailableError is returned when names are not available.
+type nameUnavailableError struct {
+	Unavailable []string
+}
+
+func (e *nameUnavailableError) Error() string {
+	return strings.Join(e.Unavailable, "\n")
+}
+
+// checkNamesAvailability checks names, by resource type, with the checker of the
+// provider meta, if any.
+func checkNamesAvailability(ctx context.Context, meta interface{}, names map[string][]string) error {
+	config, ok := meta.(*providerConfiguration)
+	if !ok || config.Availability == nil {
+		return nil
+	}
+	resourceTypeNames := make([]string, 0, len(names))
+	for resourceTypeName := range names {
+		resourceTypeNames = append(resourceTypeNames, resourceTypeName)
+	}
+	sort.Strings(resourceTypeNames)
+
+	unavailable := []string{}
+	for _, resourceTypeName := range resourceTypeNames {
+		resource, err := getResource(resourceTypeName)
+		if err != nil {
+			continue
+		}
+		for _, name := range names[resourceTypeName] {
+			if name == "" {
+				continue
+			}
+			availability, err := config.Availability.CheckNameAvailability(ctx, resource.ResourceTypeName, name)
+			if err != nil {
+				return err
+			}
+			if !availability.NameAvailable {
+				unavailable = append(unavailable, strings.TrimSpace(fmt.Sprintf("name %s of %s is not available: %s %s", name, resource.ResourceTypeName, availability.Reason, availability.Message)))
+			}
+		}
+	}
+	if len(unavailable) > 0 {
+		return &nameUnavailableError{Unavailable: unavailable}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package azurecaf
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testAvailabilityServer stands in for Azure Resource Manager. The names of taken are
+// not available, and every request is recorded.
+type testAvailabilityServer struct {
+	mu       sync.Mutex
+	taken    func(name string) bool
+	requests []map[string]string
+}
+
+func (s *testAvailabilityServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" {
+		http.Error(w, `{"error": {"code": "AuthenticationFailed"}}`, http.StatusUnauthorized)
+		return
+	}
+	var body map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	body["path"] = r.URL.Path
+	body["api-version"] = r.URL.Query().Get("api-version")
+
+	s.mu.Lock()
+	s.requests = append(s.requests, body)
+	s.mu.Unlock()
+	if s.taken(body["name"]) {
+		json.NewEncoder(w).Encode(nameAvailability{NameAvailable: false, Reason: "AlreadyExists", Message: "The name is already taken."})
+		return
+	}
+	json.NewEncoder(w).Encode(nameAvailability{NameAvailable: true})
+}
+
+func TestHTTPNameAvailabilityChecker(t *testing.T) {
+	stand := &testAvailabilityServer{taken: func(name string) bool { return name == "stapp" }}
+	server := httptest.NewServer(stand)
+	defer server.Close()
+
+	checker := &httpNameAvailabilityChecker{BaseURL: server.URL + "/", SubscriptionID: "00000000-0000-0000-0000-000000000000", Tokens: staticTokenSource("secret")}
+	availability, err := checker.CheckNameAvailability(context.Background(), "azurerm_storage_account", "stapp")
+	if err != nil {
+		t.Fatalf("CheckNameAvailability failed: %v", err)
+	}
+	if availability.NameAvailable || availability.Reason != "AlreadyExists" {
+		t.Errorf("Expected a taken name, got %+v", availability)
+	}
+	request := stand.requests[0]
+	if request["path"] != "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Storage/checkNameAvailability" || request["api-version"] == "" || request["type"] != "Microsoft.Storage/storageAccounts" {
+		t.Errorf("Unexpected request %v", request)
+	}
+
+	if availability, err := checker.CheckNameAvailability(context.Background(), "azurerm_servicebus_namespace", "sb-app"); err != nil || !availability.NameAvailable {
+		t.Errorf("Expected an available name, got %+v (%v)", availability, err)
+	}
+	if _, found := stand.requests[1]["type"]; found {
+		t.Errorf("Expected no type for service bus namespaces, got %v", stand.requests[1])
+	}
+
+	// Resource types without checkNameAvailability operation are not checked
+	if availability, err := checker.CheckNameAvailability(context.Background(), "azurerm_subnet", "stapp"); err != nil || !availability.NameAvailable || len(stand.requests) != 2 {
+		t.Errorf("Expected subnets not to be checked, got %+v (%v)", availability, err)
+	}
+
+	checker.Tokens = staticTokenSource("expired")
+	if _, err := checker.CheckNameAvailability(context.Background(), "azurerm_key_vault", "kv-app"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected an authentication error, got %v", err)
+	}
+}
+
+func testAvailabilityMeta(t *testing.T, baseURL string, rerollAttempts int) interface{} {
+	t.Helper()
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"name_availability": []interface{}{map[string]interface{}{
+			"base_url":        baseURL,
+			"subscription_id": "00000000-0000-0000-0000-000000000000",
+			"token":           "secret",
+		}},
+		"reroll_attempts": rerollAttempts,
+	})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("providerConfigure failed: %v", diags)
+	}
+	return meta
+}
+
+func TestNameAvailability_Reroll(t *testing.T) {
+	taken := map[string]bool{}
+	stand := &testAvailabilityServer{taken: func(name string) bool {
+		// The first two random parts are taken
+		if len(taken) < 2 {
+			taken[name] = true
+		}
+		return taken[name]
+	}}
+	server := httptest.NewServer(stand)
+	defer server.Close()
+
+	rd := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":           "app",
+		"resource_type":  "azurerm_key_vault",
+		"resource_types": []interface{}{"azurerm_subnet"},
+		"random_length":  5,
+	})
+	if diags := resourceNameCreate(context.Background(), rd, testAvailabilityMeta(t, server.URL, 10)); diags.HasError() {
+		t.Fatalf("resourceNameCreate failed: %v", diags)
+	}
+	result := rd.Get("result").(string)
+	if taken[result] || !strings.HasSuffix(result, rd.Get("random_string").(string)) {
+		t.Errorf("Expected a name with a new random part, got %s", result)
+	}
+	// Subnets have no checkNameAvailability operation
+	if len(stand.requests) != 3 {
+		t.Errorf("Expected 3 key vault checks, got %v", stand.requests)
+	}
+}
+
+func TestNameAvailability_Taken(t *testing.T) {
+	server := httptest.NewServer(&testAvailabilityServer{taken: func(name string) bool { return true }})
+	defer server.Close()
+	meta := testAvailabilityMeta(t, server.URL, 2)
+
+	config := map[string]interface{}{
+		"name":          "app",
+		"resource_type": "azurerm_container_registry",
+		"random_length": 5,
+	}
+	if diags := resourceNameCreate(context.Background(), schema.TestResourceDataRaw(t, resourceName().Schema, config), meta); !diags.HasError() || !strings.Contains(diags[0].Summary, "still taken after 2 random parts") {
+		t.Errorf("Expected the names to be taken after 2 attempts, got %v", diags)
+	}
+	config["random_length"] = 0
+	rd := schema.TestResourceDataRaw(t, resourceName().Schema, config)
+	if diags := resourceNameCreate(context.Background(), rd, meta); !diags.HasError() || !strings.Contains(diags[0].Summary, "crapp of azurerm_container_registry is not available: AlreadyExists") {
+		t.Errorf("Expected a name without random part to be rejected, got %v", diags)
+	}
+	if rd.Id() != "" {
+		t.Errorf("Expected no ID for a taken name, got %s", rd.Id())
+	}
+}
@@ -0,0 +1,143 @@
+package azurecaf
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenSource provides the bearer token of the requests of httpNameAvailabilityChecker.
+type tokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// staticTokenSource always returns the same token, e.g. from az account
+// get-access-token. Such tokens expire, usually after an hour, and are not renewed: an
+// expired token is reported before any request is sent.
+type staticTokenSource string
+
+func (t staticTokenSource) Token(ctx context.Context) (string, error) {
+	if expiresOn, found := tokenExpiry(string(t)); found && !clock().Before(expiresOn) {
+		return "", fmt.Errorf("the access token expired at %s, get a new one or set client_id, client_secret and tenant_id in name_availability to get tokens as they expire", expiresOn.UTC().Format(time.RFC3339))
+	}
+	return string(t), nil
+}
+
+// tokenExpiry returns the expiry of a JSON Web Token, from its exp claim. Tokens that
+// are not JSON Web Tokens have no known expiry.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(claims.Exp), 0), true
+}
+
+// tokenRenewalMargin is how long before their expiry the tokens of
+// clientSecretTokenSource are renewed.
+const tokenRenewalMargin = 5 * time.Minute
+
+// clientSecretTokenSource gets the tokens of a service principal with the client
+// credentials flow of Microsoft Entra ID, under AuthorityURL, e.g.
+// https://login.microsoftonline.com. A token is reused until shortly before it
+// expires, then a new one is requested, so that long runs keep checking names.
+type clientSecretTokenSource struct {
+	AuthorityURL string
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	// Scope is the scope of the tokens, e.g. https://management.azure.com/.default.
+	Scope  string
+	Client *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresOn time.Time
+}
+
+func (s *clientSecretTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && clock().Add(tokenRenewalMargin).Before(s.expiresOn) {
+		return s.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.ClientID},
+		"client_secret": {s.ClientSecret},
+		"scope":         {s.Scope},
+	}
+	endpoint := fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(s.AuthorityURL, "/"), s.TenantID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to get a token of client %s: %w", s.ClientID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("unable to get a token of client %s: %s %s", s.ClientID, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("unable to decode the token of client %s: %v", s.ClientID, err)
+	}
+	s.token = token.AccessToken
+	s.expiresOn = clock().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// availabilityTokenSource returns the token source of a name_availability block: the
+// token, when set, or the tokens of the service principal of client_id. Without either,
+// the requests are sent without token.
+func availabilityTokenSource(block map[string]interface{}) (tokenSource, error) {
+	if token := block["token"].(string); token != "" {
+		return staticTokenSource(token), nil
+	}
+	clientID := block["client_id"].(string)
+	if clientID == "" {
+		return nil, nil
+	}
+	tenantID := block["tenant_id"].(string)
+	clientSecret := block["client_secret"].(string)
+	if tenantID == "" || clientSecret == "" {
+		return nil, fmt.Errorf("name_availability needs tenant_id and client_secret with client_id %s", clientID)
+	}
+	return &clientSecretTokenSource{
+		AuthorityURL: block["authority_url"].(string),
+		TenantID:     tenantID,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scope:        strings.TrimSuffix(block["base_url"].(string), "/") + "/.default",
+	}, nil
+}
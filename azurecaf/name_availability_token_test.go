@@ -0,0 +1,155 @@
+package azurecaf
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testJWT(t *testing.T, expiresOn time.Time) string {
+	t.Helper()
+	claims, err := json.Marshal(map[string]interface{}{"aud": "https://management.azure.com", "exp": expiresOn.Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + encode(claims) + "." + encode([]byte("signature"))
+}
+
+func TestStaticTokenSource_Expiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock = func() time.Time { return now }
+	defer func() { clock = time.Now }()
+
+	valid := testJWT(t, now.Add(time.Hour))
+	if token, err := staticTokenSource(valid).Token(context.Background()); err != nil || token != valid {
+		t.Errorf("Expected the valid token, got %s (%v)", token, err)
+	}
+	// Tokens that are not JSON Web Tokens have no known expiry
+	if token, err := staticTokenSource("secret").Token(context.Background()); err != nil || token != "secret" {
+		t.Errorf("Expected the opaque token, got %s (%v)", token, err)
+	}
+
+	expired := testJWT(t, now.Add(-time.Minute))
+	if _, err := staticTokenSource(expired).Token(context.Background()); err == nil || !strings.Contains(err.Error(), "the access token expired at 2026-01-01T11:59:00Z") {
+		t.Errorf("Expected the expiry to be reported, got %v", err)
+	}
+
+	// The checker reports it before sending any request
+	stand := &testAvailabilityServer{taken: func(name string) bool { return false }}
+	server := httptest.NewServer(stand)
+	defer server.Close()
+	checker := &httpNameAvailabilityChecker{BaseURL: server.URL, SubscriptionID: "00000000-0000-0000-0000-000000000000", Tokens: staticTokenSource(expired)}
+	if _, err := checker.CheckNameAvailability(context.Background(), "azurerm_key_vault", "kv-app"); err == nil || !strings.Contains(err.Error(), "expired") || len(stand.requests) != 0 {
+		t.Errorf("Expected the expired token to fail without request, got %v", err)
+	}
+}
+
+// testTokenServer stands in for Microsoft Entra ID, issuing secret tokens valid for an hour.
+type testTokenServer struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (s *testTokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.URL.Path != "/00000000-0000-0000-0000-000000000001/oauth2/v2.0/token" || r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("client_secret") != "client-secret" {
+		http.Error(w, `{"error": "invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+	fmt.Fprintf(w, `{"token_type": "Bearer", "expires_in": 3600, "access_token": "secret", "scope": %q}`, r.PostForm.Get("scope"))
+}
+
+func TestClientSecretTokenSource(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock = func() time.Time { return now }
+	defer func() { clock = time.Now }()
+
+	issuer := &testTokenServer{}
+	tokenServer := httptest.NewServer(issuer)
+	defer tokenServer.Close()
+	stand := &testAvailabilityServer{taken: func(name string) bool { return false }}
+	server := httptest.NewServer(stand)
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"name_availability": []interface{}{map[string]interface{}{
+			"base_url":        server.URL,
+			"subscription_id": "00000000-0000-0000-0000-000000000000",
+			"token":           "",
+			"tenant_id":       "00000000-0000-0000-0000-000000000001",
+			"client_id":       "00000000-0000-0000-0000-000000000002",
+			"client_secret":   "client-secret",
+			"authority_url":   tokenServer.URL,
+		}},
+	})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("providerConfigure failed: %v", diags)
+	}
+	checker := meta.(*providerConfiguration).Availability
+
+	// The token is reused until shortly before it expires
+	for _, elapsed := range []time.Duration{0, 30 * time.Minute, 54 * time.Minute} {
+		now = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC).Add(elapsed)
+		if _, err := checker.CheckNameAvailability(context.Background(), "azurerm_key_vault", "kv-app"); err != nil {
+			t.Fatalf("CheckNameAvailability failed after %s: %v", elapsed, err)
+		}
+	}
+	if issuer.requests != 1 {
+		t.Errorf("Expected one token, got %d", issuer.requests)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := checker.CheckNameAvailability(context.Background(), "azurerm_key_vault", "kv-app"); err != nil {
+		t.Fatalf("CheckNameAvailability failed: %v", err)
+	}
+	if issuer.requests != 2 || len(stand.requests) != 4 {
+		t.Errorf("Expected a new token before the expiry, got %d tokens for %d requests", issuer.requests, len(stand.requests))
+	}
+
+	// A rejected secret is reported
+	tokens := &clientSecretTokenSource{AuthorityURL: tokenServer.URL, TenantID: "00000000-0000-0000-0000-000000000001", ClientID: "app", ClientSecret: "wrong"}
+	if _, err := tokens.Token(context.Background()); err == nil || !strings.Contains(err.Error(), "unable to get a token of client app: 401") {
+		t.Errorf("Expected the token request to fail, got %v", err)
+	}
+}
+
+func TestAvailabilityTokenSource(t *testing.T) {
+	block := map[string]interface{}{
+		"base_url":      "https://management.azure.com/",
+		"token":         "",
+		"tenant_id":     "",
+		"client_id":     "",
+		"client_secret": "",
+		"authority_url": "https://login.microsoftonline.com",
+	}
+	if tokens, err := availabilityTokenSource(block); err != nil || tokens != nil {
+		t.Errorf("Expected no token source, got %v (%v)", tokens, err)
+	}
+	block["client_id"] = "app"
+	if _, err := availabilityTokenSource(block); err == nil || !strings.Contains(err.Error(), "needs tenant_id and client_secret") {
+		t.Errorf("Expected an incomplete service principal to fail, got %v", err)
+	}
+	block["tenant_id"] = "tenant"
+	block["client_secret"] = "client-secret"
+	tokens, err := availabilityTokenSource(block)
+	if source, ok := tokens.(*clientSecretTokenSource); err != nil || !ok || source.Scope != "https://management.azure.com/.default" {
+		t.Errorf("Expected the tokens of the service principal, got %#v (%v)", tokens, err)
+	}
+	// The token takes precedence
+	block["token"] = "secret"
+	if tokens, err := availabilityTokenSource(block); err != nil || tokens != staticTokenSource("secret") {
+		t.Errorf("Expected the token, got %#v (%v)", tokens, err)
+	}
+}
@@ -0,0 +1,259 @@
+package azurecaf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Behaviors of azurecaf_name when a name is already reserved in the ledger.
+const (
+	LedgerCollisionReject = "reject"
+	LedgerCollisionReroll = "reroll"
+)
+
+const (
+	// ledgerVersion is the version of the ledger file format.
+	ledgerVersion = 1
+	// ledgerLockTimeout is how long to wait for the lock of the ledger.
+	ledgerLockTimeout = 30 * time.Second
+	// ledgerStaleLock is the age after which a lock left by a crashed run is removed.
+	ledgerStaleLock = 5 * time.Minute
+)
+
+// nameLedger is a JSON file recording the names reserved by azurecaf_name resources
+// across workspaces and state files. Every access holds a lock file next to the
+// ledger, so that concurrent runs sharing the ledger see each other's names.
+type nameLedger struct {
+	Path      string
+	Workspace string
+	Collision string
+}
+
+// ledgerFile is the content of the ledger file.
+type ledgerFile struct {
+	Version int           `json:"version"`
+	Names   []ledgerEntry `json:"names"`
+}
+
+// ledgerEntry is a name reserved by an azurecaf_name resource, its owner, of a workspace.
+type ledgerEntry struct {
+	Name         string `json:"name"`
+	ResourceType string `json:"resource_type"`
+	Scope        string `json:"scope"`
+	ScopeKey     string `json:"scope_key,omitempty"`
+	Workspace    string `json:"workspace"`
+	Owner        string `json:"owner"`
+	ReservedAt   string `json:"reserved_at"`
+}
+
+func (e ledgerEntry) key() nameRegistryKey {
+	return nameRegistryKey{ResourceType: e.ResourceType, Scope: e.Scope, ScopeKey: e.ScopeKey, Name: strings.ToLower(e.Name)}
+}
+
+// ledgerCollisionError is returned when names are reserved by another owner.
+type ledgerCollisionError struct {
+	Collisions []string
+}
+
+func (e *ledgerCollisionError) Error() string {
+	return strings.Join(e.Collisions, "\n")
+}
+
+// reserve records names, by resource type, for owner. The names owner reserved before
+// and no longer uses are released. Nothing is recorded when a name is reserved by
+// another owner, a *ledgerCollisionError lists them.
+func (l *nameLedger) reserve(owner string, scopeKey string, names map[string][]string) error {
+	return l.update(func(ledger *ledgerFile) error {
+		entries := []ledgerEntry{}
+		for resourceTypeName, resourceNames := range names {
+			resource, err := getResource(resourceTypeName)
+			if err != nil {
+				continue
+			}
+			for _, name := range resourceNames {
+				if name == "" {
+					continue
+				}
+				entry := ledgerEntry{Name: name, ResourceType: resource.ResourceTypeName, Scope: resource.Scope, ScopeKey: scopeKey, Workspace: l.Workspace, Owner: owner}
+				if entry.Scope == ScopeGlobal {
+					entry.ScopeKey = ""
+				}
+				entries = append(entries, entry)
+			}
+		}
+
+		reserved := map[nameRegistryKey]ledgerEntry{}
+		for _, entry := range ledger.Names {
+			if !l.owns(entry, owner) {
+				reserved[entry.key()] = entry
+			}
+		}
+		collisions := []string{}
+		for _, entry := range entries {
+			if existing, found := reserved[entry.key()]; found {
+				collisions = append(collisions, fmt.Sprintf("name %s of %s is already reserved in the %s by %s of workspace %s", entry.Name, entry.ResourceType, entry.key().scopeDescription(), existing.Owner, existing.Workspace))
+			}
+		}
+		if len(collisions) > 0 {
+			sort.Strings(collisions)
+			return &ledgerCollisionError{Collisions: collisions}
+		}
+
+		reservedAt := clock().UTC().Format(time.RFC3339)
+		kept := []ledgerEntry{}
+		for _, entry := range ledger.Names {
+			if !l.owns(entry, owner) {
+				kept = append(kept, entry)
+			}
+		}
+		for _, entry := range entries {
+			entry.ReservedAt = reservedAt
+			kept = append(kept, entry)
+		}
+		ledger.Names = kept
+		return nil
+	})
+}
+
+// release removes the names reserved by owner.
+func (l *nameLedger) release(owner string) error {
+	return l.update(func(ledger *ledgerFile) error {
+		kept := []ledgerEntry{}
+		for _, entry := range ledger.Names {
+			if !l.owns(entry, owner) {
+				kept = append(kept, entry)
+			}
+		}
+		ledger.Names = kept
+		return nil
+	})
+}
+
+func (l *nameLedger) owns(entry ledgerEntry, owner string) bool {
+	return entry.Owner == owner && entry.Workspace == l.Workspace
+}
+
+// update applies change to the content of the ledger under its lock, and writes the
+// ledger when change succeeds.
+func (l *nameLedger) update(change func(*ledgerFile) error) error {
+	token, err := l.lock()
+	if err != nil {
+		return err
+	}
+	defer l.unlock(token)
+
+	ledger := &ledgerFile{Version: ledgerVersion, Names: []ledgerEntry{}}
+	content, err := os.ReadFile(l.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to read the name ledger %s: %w", l.Path, err)
+	}
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, ledger); err != nil {
+			return fmt.Errorf("unable to parse the name ledger %s: %w", l.Path, err)
+		}
+		if ledger.Version != ledgerVersion {
+			return fmt.Errorf("unsupported version %d of the name ledger %s", ledger.Version, l.Path)
+		}
+	}
+
+	if err := change(ledger); err != nil {
+		return err
+	}
+
+	content, err = json.MarshalIndent(ledger, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write a temporary file renamed over the ledger so that it is never left partially written
+	temporary, err := os.CreateTemp(filepath.Dir(l.Path), filepath.Base(l.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to write the name ledger %s: %w", l.Path, err)
+	}
+	_, err = temporary.Write(append(content, '\n'))
+	if closeErr := temporary.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && !l.ownsLock(token) {
+		err = fmt.Errorf("the lock %s.lock was taken over by another run", l.Path)
+	}
+	if err == nil {
+		err = os.Rename(temporary.Name(), l.Path)
+	}
+	if err != nil {
+		os.Remove(temporary.Name())
+		return fmt.Errorf("unable to write the name ledger %s: %w", l.Path, err)
+	}
+	return nil
+}
+
+// lock creates the lock file of the ledger, waiting for other runs to remove theirs.
+// Creating a file exclusively works on every platform and file system, unlike flock.
+// The returned token identifies the run in the lock file, see ownsLock.
+func (l *nameLedger) lock() (string, error) {
+	path := l.Path + ".lock"
+	token := fmt.Sprintf("%d %s", os.Getpid(), randSeq(16, nil))
+	deadline := time.Now().Add(ledgerLockTimeout)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintln(file, token)
+			file.Close()
+			return token, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("unable to lock the name ledger %s: %w", l.Path, err)
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > ledgerStaleLock {
+			removeStaleLock(path, token)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("unable to lock the name ledger %s: %s is held by another run", l.Path, path)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// unlock removes the lock file of the ledger, unless another run took it over.
+func (l *nameLedger) unlock(token string) {
+	if l.ownsLock(token) {
+		os.Remove(l.Path + ".lock")
+	}
+}
+
+// ownsLock reports whether the lock file of the ledger still holds token. It is
+// checked before the ledger is written, in case another run took over the lock.
+func (l *nameLedger) ownsLock(token string) bool {
+	content, err := os.ReadFile(l.Path + ".lock")
+	return err == nil && strings.TrimSpace(string(content)) == token
+}
+
+// removeStaleLock removes the lock file at path, left by a crashed run. Runs finding
+// the same stale lock would all remove it, including a lock created again in the
+// meantime: the lock is renamed instead, which only one run can do, and put back when
+// the renamed lock turns out not to be stale.
+func removeStaleLock(path string, token string) {
+	stale := fmt.Sprintf("%s.%s.stale", path, strings.ReplaceAll(token, " ", "-"))
+	if err := os.Rename(path, stale); err != nil {
+		return
+	}
+	if info, err := os.Stat(stale); err == nil && time.Since(info.ModTime()) <= ledgerStaleLock {
+		// Linking fails when yet another run created the lock since
+		os.Link(stale, path)
+	}
+	os.Remove(stale)
+}
+
+// ledgerFromMeta returns the name ledger of the provider configuration, if any.
+func ledgerFromMeta(meta interface{}) *nameLedger {
+	if config, ok := meta.(*providerConfiguration); ok {
+		return config.Ledger
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package azurecaf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func testLedgerMeta(t *testing.T, path string, workspace string, collision string) interface{} {
+	t.Helper()
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"ledger_file":       path,
+		"ledger_workspace":  workspace,
+		"ledger_collision":  collision,
+		"on_duplicate_name": DuplicateNameIgnore,
+	})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("providerConfigure failed: %v", diags)
+	}
+	return meta
+}
+
+func readTestLedger(t *testing.T, path string) ledgerFile {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ledger ledgerFile
+	if err := json.Unmarshal(content, &ledger); err != nil {
+		t.Fatal(err)
+	}
+	return ledger
+}
+
+func TestNameLedger_Reserve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.json")
+	landingZone := &nameLedger{Path: path, Workspace: "landing-zone"}
+	application := &nameLedger{Path: path, Workspace: "application"}
+
+	if err := landingZone.reserve("rg", "", map[string][]string{"azurerm_resource_group": {"rg-app"}, "azurerm_key_vault": {"kv-app"}}); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	// The same owner reserves its names again, and releases the ones it no longer uses
+	if err := landingZone.reserve("rg", "", map[string][]string{"azurerm_key_vault": {"kv-app"}}); err != nil {
+		t.Fatalf("reserve failed for the same owner: %v", err)
+	}
+	if ledger := readTestLedger(t, path); len(ledger.Names) != 1 || ledger.Names[0].Scope != ScopeGlobal || ledger.Names[0].Workspace != "landing-zone" {
+		t.Errorf("Unexpected ledger %+v", ledger)
+	}
+
+	// The same owner ID in another workspace is another owner
+	err := application.reserve("rg", "rg-other", map[string][]string{"azurerm_key_vault": {"KV-APP"}})
+	var collision *ledgerCollisionError
+	if !errors.As(err, &collision) || !strings.Contains(err.Error(), "reserved in the global scope by rg of workspace landing-zone") {
+		t.Errorf("Expected a collision, got %v", err)
+	}
+	if err := application.reserve("subnet", "vnet-app", map[string][]string{"azurerm_subnet": {"snet-app"}}); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	if err := landingZone.reserve("subnet", "vnet-other", map[string][]string{"azurerm_subnet": {"snet-app"}}); err != nil {
+		t.Errorf("Expected names of other parents to be free, got %v", err)
+	}
+
+	if err := landingZone.release("rg"); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if err := application.reserve("kv", "", map[string][]string{"azurerm_key_vault": {"kv-app"}}); err != nil {
+		t.Errorf("Expected the released name to be free, got %v", err)
+	}
+}
+
+func TestNameLedger_Lock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.json")
+	ledger := &nameLedger{Path: path, Workspace: "default"}
+
+	// A lock left by a crashed run is removed once stale
+	if err := os.WriteFile(path+".lock", []byte("1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-2 * ledgerStaleLock)
+	if err := os.Chtimes(path+".lock", stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.reserve("kv", "", map[string][]string{"azurerm_key_vault": {"kv-app"}}); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	if _, err := os.Stat(path + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the lock to be removed, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 2, "names": []}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.release("kv"); err == nil || !strings.Contains(err.Error(), "unsupported version 2") {
+		t.Errorf("Expected a version error, got %v", err)
+	}
+}
+
+func TestNameLedger_StaleLockTakeOver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.json")
+	ledger := &nameLedger{Path: path, Workspace: "default"}
+
+	// A run that found the lock stale puts back the lock created again since
+	token, err := ledger.lock()
+	if err != nil {
+		t.Fatalf("lock failed: %v", err)
+	}
+	removeStaleLock(path+".lock", "1 other")
+	if !ledger.ownsLock(token) {
+		t.Error("Expected a fresh lock to be put back")
+	}
+	if matches, _ := filepath.Glob(path + ".lock.*"); len(matches) != 0 {
+		t.Errorf("Expected no renamed lock left, got %v", matches)
+	}
+
+	// A lock taken over by another run is neither written through nor removed
+	if err := os.WriteFile(path+".lock", []byte("1 other\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if ledger.ownsLock(token) {
+		t.Error("Expected the lock to be owned by the other run")
+	}
+	ledger.unlock(token)
+	if _, err := os.Stat(path + ".lock"); err != nil {
+		t.Errorf("Expected the lock of the other run to be kept, got %v", err)
+	}
+}
+
+func TestNameLedger_Resource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.json")
+	config := map[string]interface{}{
+		"name":          "app",
+		"resource_type": "azurerm_key_vault",
+	}
+
+	landingZone := testLedgerMeta(t, path, "landing-zone", LedgerCollisionReject)
+	first := schema.TestResourceDataRaw(t, resourceName().Schema, config)
+	if diags := resourceNameCreate(context.Background(), first, landingZone); diags.HasError() {
+		t.Fatalf("resourceNameCreate failed: %v", diags)
+	}
+
+	application := testLedgerMeta(t, path, "application", LedgerCollisionReject)
+	second := schema.TestResourceDataRaw(t, resourceName().Schema, config)
+	if diags := resourceNameCreate(context.Background(), second, application); !diags.HasError() || !strings.Contains(diags[0].Summary, "kv-app") {
+		t.Errorf("Expected the reserved name to be rejected, got %v", diags)
+	}
+
+	// Re-rolling only helps names with a random part
+	reroll := testLedgerMeta(t, path, "application", LedgerCollisionReroll)
+	if diags := resourceNameCreate(context.Background(), schema.TestResourceDataRaw(t, resourceName().Schema, config), reroll); !diags.HasError() {
+		t.Error("Expected the reserved name without random part to be rejected")
+	}
+	config["random_length"] = 5
+	config["random_seed"] = 42
+	seeded := schema.TestResourceDataRaw(t, resourceName().Schema, config)
+	if diags := resourceNameCreate(context.Background(), seeded, landingZone); diags.HasError() {
+		t.Fatalf("resourceNameCreate failed: %v", diags)
+	}
+	rerolled := schema.TestResourceDataRaw(t, resourceName().Schema, config)
+	if diags := resourceNameCreate(context.Background(), rerolled, reroll); diags.HasError() {
+		t.Fatalf("resourceNameCreate failed: %v", diags)
+	}
+	if rerolled.Get("result").(string) == seeded.Get("result").(string) || rerolled.Get("random_string").(string) == seeded.Get("random_string").(string) {
+		t.Errorf("Expected a new random part, got %s twice", seeded.Get("result").(string))
+	}
+
+	// Destroying the first resource releases its name
+	if err := resourceNameDelete(first, landingZone); err != nil {
+		t.Fatalf("resourceNameDelete failed: %v", err)
+	}
+	if first.Id() != "" {
+		t.Errorf("Expected the resource to be removed from the state, got %s", first.Id())
+	}
+	delete(config, "random_length")
+	delete(config, "random_seed")
+	if diags := resourceNameCreate(context.Background(), schema.TestResourceDataRaw(t, resourceName().Schema, config), application); diags.HasError() {
+		t.Errorf("Expected the released name to be free, got %v", diags)
+	}
+	if ledger := readTestLedger(t, path); len(ledger.Names) != 3 {
+		t.Errorf("Expected 3 reserved names, got %+v", ledger.Names)
+	}
+}
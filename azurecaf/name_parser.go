@@ -0,0 +1,163 @@
+package azurecaf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Confidence levels reported when an existing name is decomposed into its
+// convention components.
+const (
+	// NameConfidenceHigh means the slug was found exactly once between separators
+	// and the components reproduce the name.
+	NameConfidenceHigh = "high"
+	// NameConfidenceMedium means the components reproduce the name but the split is
+	// ambiguous, e.g. the slug appears several times or the name has no separator.
+	NameConfidenceMedium = "medium"
+	// NameConfidenceLow means no slug was found, the whole name is used as the base
+	// name without slug.
+	NameConfidenceLow = "low"
+	// NameConfidenceNone means the name cannot be reproduced by the naming pipeline,
+	// typically because it does not comply with the resource definition.
+	NameConfidenceNone = "none"
+)
+
+// parsedName holds the convention components an existing name was built from.
+// Composing Prefixes, Slug, Name, Random and Suffixes with Separator through
+// getResourceName gives back the original name unless Confidence is
+// NameConfidenceNone.
+type parsedName struct {
+	Prefixes   []string
+	Slug       string
+	Name       string
+	Random     string
+	Suffixes   []string
+	Separator  string
+	UseSlug    bool
+	Confidence string
+}
+
+// parseName reverses the naming pipeline: it decomposes an existing name into
+// prefixes, slug, base name, random part and suffixes for the given resource type.
+//
+// The slug comes from ResourceDefinitions. Tokens before the slug are prefixes, the
+// token after it is the base name and the remaining tokens are suffixes. When
+// randomLength is set, a token of that length made of random characters following
+// the base name is reported as the random part. Every candidate decomposition is
+// checked by composing it again, the first one reproducing the name is returned.
+func parseName(name string, resourceType string, separator string, randomLength int) (parsedName, error) {
+	if randomLength < 0 {
+		return parsedName{}, fmt.Errorf("random_length must be non-negative, got: %d", randomLength)
+	}
+	resource, err := getResource(resourceType)
+	if err != nil {
+		return parsedName{}, err
+	}
+
+	candidates := []parsedName{}
+	slug := resource.CafPrefix
+	effectiveSeparator, err := cleanString(separator, resource)
+	if err != nil {
+		return parsedName{}, err
+	}
+
+	if slug != "" && effectiveSeparator != "" {
+		tokens := strings.Split(name, effectiveSeparator)
+		positions := []int{}
+		for i, token := range tokens[:len(tokens)-1] {
+			if token == slug {
+				positions = append(positions, i)
+			}
+		}
+		confidence := NameConfidenceHigh
+		if len(positions) > 1 {
+			confidence = NameConfidenceMedium
+		}
+		for _, i := range positions {
+			candidate := parsedName{
+				Prefixes:   nonEmptyTokens(tokens[:i]),
+				Slug:       slug,
+				Name:       tokens[i+1],
+				Separator:  separator,
+				UseSlug:    true,
+				Confidence: confidence,
+			}
+			remaining := tokens[i+2:]
+			if len(remaining) > 0 && isRandomPart(remaining[0], randomLength) {
+				candidate.Random = remaining[0]
+				remaining = remaining[1:]
+			}
+			candidate.Suffixes = nonEmptyTokens(remaining)
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	// Without separator in the composed name, only the leading slug can be recognized
+	if slug != "" && strings.HasPrefix(name, slug) && len(name) > len(slug) {
+		candidate := parsedName{
+			Slug:       slug,
+			Name:       name[len(slug):],
+			Separator:  separator,
+			UseSlug:    true,
+			Confidence: NameConfidenceMedium,
+		}
+		if randomLength > 0 && len(candidate.Name) > randomLength && isRandomPart(candidate.Name[len(candidate.Name)-randomLength:], randomLength) {
+			candidate.Random = candidate.Name[len(candidate.Name)-randomLength:]
+			candidate.Name = candidate.Name[:len(candidate.Name)-randomLength]
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	candidates = append(candidates, parsedName{
+		Name:       name,
+		Separator:  separator,
+		Confidence: NameConfidenceLow,
+	})
+
+	for _, candidate := range candidates {
+		if candidate.compose(resourceType) == name {
+			return candidate, nil
+		}
+	}
+	return parsedName{Name: name, Separator: separator, Confidence: NameConfidenceNone}, nil
+}
+
+// compose runs the components through the naming pipeline. An empty string is
+// returned when the components do not produce a valid name.
+func (p parsedName) compose(resourceType string) string {
+	namePrecedence := []string{"name", "slug", "random", "suffixes", "prefixes"}
+	// getResourceName cleans prefixes and suffixes in place
+	prefixes := append([]string(nil), p.Prefixes...)
+	suffixes := append([]string(nil), p.Suffixes...)
+	name, err := getResourceName(resourceType, p.Separator, prefixes, p.Name, suffixes, p.Random, ConventionCafClassic, true, false, p.UseSlug, namePrecedence)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// nonEmptyTokens returns tokens without the empty ones, found between two separators.
+// They are never part of a composed name and are rejected by the prefixes and suffixes
+// arguments.
+func nonEmptyTokens(tokens []string) []string {
+	kept := []string{}
+	for _, token := range tokens {
+		if token != "" {
+			kept = append(kept, token)
+		}
+	}
+	return kept
+}
+
+// isRandomPart reports whether token could have been generated by randSeq.
+func isRandomPart(token string, length int) bool {
+	if length <= 0 || len(token) != length {
+		return false
+	}
+	for _, r := range token {
+		if !strings.ContainsRune(string(alphagenerator), r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package azurecaf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		resourceType string
+		separator    string
+		randomLength int
+		expected     parsedName
+	}{
+		{
+			name:         "prefixes name and suffixes",
+			input:        "corp-rg-myapp-prod-001",
+			resourceType: "azurerm_resource_group",
+			separator:    "-",
+			expected:     parsedName{Prefixes: []string{"corp"}, Slug: "rg", Name: "myapp", Suffixes: []string{"prod", "001"}, UseSlug: true, Confidence: NameConfidenceHigh},
+		},
+		{
+			name:         "random part after the name",
+			input:        "rg-myapp-xvlbz-prod",
+			resourceType: "azurerm_resource_group",
+			separator:    "-",
+			randomLength: 5,
+			expected:     parsedName{Slug: "rg", Name: "myapp", Random: "xvlbz", Suffixes: []string{"prod"}, UseSlug: true, Confidence: NameConfidenceHigh},
+		},
+		{
+			name:         "slug appearing twice",
+			input:        "rg-rg-app",
+			resourceType: "azurerm_resource_group",
+			separator:    "-",
+			expected:     parsedName{Slug: "rg", Name: "rg", Suffixes: []string{"app"}, UseSlug: true, Confidence: NameConfidenceMedium},
+		},
+		{
+			name:         "resource without dashes",
+			input:        "stmyappabc",
+			resourceType: "azurerm_storage_account",
+			separator:    "-",
+			randomLength: 3,
+			expected:     parsedName{Slug: "st", Name: "myapp", Random: "abc", UseSlug: true, Confidence: NameConfidenceMedium},
+		},
+		{
+			name:         "name without slug",
+			input:        "my-resource-group",
+			resourceType: "azurerm_resource_group",
+			separator:    "-",
+			expected:     parsedName{Name: "my-resource-group", Confidence: NameConfidenceLow},
+		},
+		{
+			name:         "empty tokens between separators",
+			input:        "corp--rg-myapp-001-",
+			resourceType: "azurerm_resource_group",
+			separator:    "-",
+			expected:     parsedName{Name: "corp--rg-myapp-001-", Confidence: NameConfidenceLow},
+		},
+		{
+			name:         "non compliant name",
+			input:        "Invalid_Storage",
+			resourceType: "azurerm_storage_account",
+			separator:    "-",
+			expected:     parsedName{Name: "Invalid_Storage", Confidence: NameConfidenceNone},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseName(tt.input, tt.resourceType, tt.separator, tt.randomLength)
+			if err != nil {
+				t.Fatalf("parseName failed: %v", err)
+			}
+			if strings.Join(parsed.Prefixes, ",") != strings.Join(tt.expected.Prefixes, ",") ||
+				parsed.Slug != tt.expected.Slug ||
+				parsed.Name != tt.expected.Name ||
+				parsed.Random != tt.expected.Random ||
+				strings.Join(parsed.Suffixes, ",") != strings.Join(tt.expected.Suffixes, ",") ||
+				parsed.UseSlug != tt.expected.UseSlug ||
+				parsed.Confidence != tt.expected.Confidence {
+				t.Errorf("Expected %+v, got %+v", tt.expected, parsed)
+			}
+			for _, token := range append(append([]string{}, parsed.Prefixes...), parsed.Suffixes...) {
+				if token == "" {
+					t.Errorf("Expected no empty prefix or suffix, got %+v", parsed)
+				}
+			}
+			if parsed.Confidence != NameConfidenceNone && parsed.compose(tt.resourceType) != tt.input {
+				t.Errorf("Components %+v do not reproduce %s", parsed, tt.input)
+			}
+		})
+	}
+}
+
+func TestParseName_Errors(t *testing.T) {
+	if _, err := parseName("rg-app", "azurerm_not_covered", "-", 0); err == nil {
+		t.Error("Expected an error for an unknown resource type")
+	}
+	if _, err := parseName("rg-app", "azurerm_resource_group", "-", -1); err == nil {
+		t.Error("Expected an error for a negative random length")
+	}
+}
+
+func TestDataNameComponentsRead(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataNameComponents().Schema, map[string]interface{}{
+		"name":          "corp-kv-myapp-001",
+		"resource_type": "azurerm_key_vault",
+	})
+	if diags := dataNameComponentsRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	if d.Get("slug").(string) != "kv" || d.Get("base_name").(string) != "myapp" || d.Get("confidence").(string) != NameConfidenceHigh {
+		t.Errorf("Unexpected components: slug=%v base_name=%v confidence=%v", d.Get("slug"), d.Get("base_name"), d.Get("confidence"))
+	}
+	if prefixes := d.Get("prefixes").([]interface{}); len(prefixes) != 1 || prefixes[0] != "corp" {
+		t.Errorf("Unexpected prefixes %v", prefixes)
+	}
+	if suffixes := d.Get("suffixes").([]interface{}); len(suffixes) != 1 || suffixes[0] != "001" {
+		t.Errorf("Unexpected suffixes %v", suffixes)
+	}
+	if d.Id() != "azurerm_key_vault:corp-kv-myapp-001" {
+		t.Errorf("Unexpected id %s", d.Id())
+	}
+}
+
+func TestResourceNameImport_WithSeparator(t *testing.T) {
+	d := resourceName().TestResourceData()
+	d.SetId("azurerm_resource_group:corp-rg-myapp-001:-")
+
+	result, err := resourceNameImport(d, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	imported := result[0]
+	if imported.Get("passthrough").(bool) {
+		t.Error("Expected a decomposed name not to use passthrough")
+	}
+	if imported.Get("name").(string) != "myapp" || imported.Get("result").(string) != "corp-rg-myapp-001" {
+		t.Errorf("Unexpected import name=%v result=%v", imported.Get("name"), imported.Get("result"))
+	}
+
+	// The imported arguments must generate the imported name again
+	generated, _, err := generateNames(nameParameters{
+		Name:         imported.Get("name").(string),
+		Prefixes:     convertInterfaceToString(imported.Get("prefixes").([]interface{})),
+		Suffixes:     convertInterfaceToString(imported.Get("suffixes").([]interface{})),
+		Separator:    imported.Get("separator").(string),
+		ResourceType: imported.Get("resource_type").(string),
+		CleanInput:   true,
+		UseSlug:      imported.Get("use_slug").(bool),
+	})
+	if err != nil || generated != "corp-rg-myapp-001" {
+		t.Errorf("Expected the imported arguments to generate corp-rg-myapp-001, got %s (%v)", generated, err)
+	}
+
+	// Names that cannot be decomposed fall back to passthrough
+	d = resourceName().TestResourceData()
+	d.SetId("azurerm_resource_group:my-resource-group:-")
+	result, err = resourceNameImport(d, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result[0].Get("passthrough").(bool) || result[0].Get("name").(string) != "my-resource-group" {
+		t.Errorf("Expected passthrough import, got passthrough=%v name=%v", result[0].Get("passthrough"), result[0].Get("name"))
+	}
+}
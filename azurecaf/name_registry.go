@@ -0,0 +1,114 @@
+package azurecaf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// Behaviors of the provider when two names generated during the same run collide.
+const (
+	DuplicateNameWarn   = "warn"
+	DuplicateNameError  = "error"
+	DuplicateNameIgnore = "ignore"
+)
+
+// nameRegistry records the names generated during a run of the provider, so that two
+// azurecaf_name resources or data sources generating the same name in the same scope
+// are reported. Terraform evaluates resources concurrently, the registry is guarded by
+// a mutex.
+type nameRegistry struct {
+	mu     sync.Mutex
+	owners map[nameRegistryKey]string
+}
+
+// nameRegistryKey identifies a name in its scope. The scope key, e.g. the name of a
+// resource group, is ignored for the global scope.
+type nameRegistryKey struct {
+	ResourceType string
+	Scope        string
+	ScopeKey     string
+	Name         string
+}
+
+func newNameRegistry() *nameRegistry {
+	return &nameRegistry{owners: map[nameRegistryKey]string{}}
+}
+
+// register records the names generated by owner, by resource type, and returns a
+// message for every name already generated by another owner in the same scope. Names
+// are compared without case. An empty owner never matches, so that the owners without
+// identity, e.g. the specs of azurecaf_names, report every repeated name.
+func (r *nameRegistry) register(owner string, scopeKey string, names map[string][]string) []string {
+	resourceTypeNames := make([]string, 0, len(names))
+	for resourceTypeName := range names {
+		resourceTypeNames = append(resourceTypeNames, resourceTypeName)
+	}
+	sort.Strings(resourceTypeNames)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	duplicates := []string{}
+	for _, resourceTypeName := range resourceTypeNames {
+		resource, err := getResource(resourceTypeName)
+		if err != nil {
+			continue
+		}
+		for _, name := range names[resourceTypeName] {
+			if name == "" {
+				continue
+			}
+			key := nameRegistryKey{ResourceType: resource.ResourceTypeName, Scope: resource.Scope, ScopeKey: scopeKey, Name: strings.ToLower(name)}
+			if key.Scope == ScopeGlobal {
+				key.ScopeKey = ""
+			}
+			if existing, found := r.owners[key]; found && (owner == "" || existing != owner) {
+				duplicates = append(duplicates, fmt.Sprintf("name %s of %s is generated more than once in the %s", name, key.ResourceType, key.scopeDescription()))
+				continue
+			}
+			r.owners[key] = owner
+		}
+	}
+	return duplicates
+}
+
+func (k nameRegistryKey) scopeDescription() string {
+	scope := k.Scope
+	if scope == "" {
+		scope = "unknown"
+	}
+	if k.ScopeKey == "" {
+		return scope + " scope"
+	}
+	return fmt.Sprintf("%s scope %s", scope, k.ScopeKey)
+}
+
+// registerNames records the names generated by owner in the registry of the provider
+// meta. Duplicates are an error when on_duplicate_name is error, and are otherwise
+// returned as warnings for Terraform to show.
+func registerNames(meta interface{}, owner string, scopeKey string, names map[string][]string) (diag.Diagnostics, error) {
+	config, ok := meta.(*providerConfiguration)
+	if !ok || config.Names == nil || config.OnDuplicateName == DuplicateNameIgnore {
+		return nil, nil
+	}
+	duplicates := config.Names.register(owner, scopeKey, names)
+	if len(duplicates) == 0 {
+		return nil, nil
+	}
+	if config.OnDuplicateName == DuplicateNameError {
+		return nil, fmt.Errorf("%s", strings.Join(duplicates, "\n"))
+	}
+	var diags diag.Diagnostics
+	for _, duplicate := range duplicates {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Duplicate name",
+			Detail:   duplicate,
+		})
+	}
+	return diags, nil
+}
@@ -0,0 +1,128 @@
+package azurecaf
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestNameRegistry_Register(t *testing.T) {
+	registry := newNameRegistry()
+	if duplicates := registry.register("first", "rg-app", map[string][]string{"azurerm_key_vault": {"kv-prd-app"}, "azurerm_subnet": {"snet-app"}}); len(duplicates) != 0 {
+		t.Fatalf("Expected no duplicate, got %v", duplicates)
+	}
+
+	tests := map[string]struct {
+		owner    string
+		scopeKey string
+		names    map[string][]string
+		expected string
+	}{
+		"same owner":               {"first", "rg-app", map[string][]string{"azurerm_key_vault": {"kv-prd-app"}}, ""},
+		"global scope":             {"second", "rg-other", map[string][]string{"azurerm_key_vault": {"KV-PRD-APP"}}, "name KV-PRD-APP of azurerm_key_vault is generated more than once in the global scope"},
+		"same parent":              {"second", "rg-app", map[string][]string{"azurerm_subnet": {"snet-app"}}, "name snet-app of azurerm_subnet is generated more than once in the parent scope rg-app"},
+		"other parent":             {"second", "rg-other", map[string][]string{"azurerm_subnet": {"snet-app"}}, ""},
+		"other resource type":      {"second", "rg-app", map[string][]string{"azurerm_storage_account": {"kv-prd-app"}}, ""},
+		"data source without name": {"", "rg-app", map[string][]string{"azurerm_subnet": {"snet-app"}}, "generated more than once"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			duplicates := registry.register(tt.owner, tt.scopeKey, tt.names)
+			if tt.expected == "" && len(duplicates) != 0 {
+				t.Errorf("Expected no duplicate, got %v", duplicates)
+			}
+			if tt.expected != "" && (len(duplicates) != 1 || !strings.Contains(duplicates[0], tt.expected)) {
+				t.Errorf("Expected a duplicate containing %q, got %v", tt.expected, duplicates)
+			}
+		})
+	}
+}
+
+func TestNameRegistry_Concurrent(t *testing.T) {
+	registry := newNameRegistry()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	duplicates := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(owner string) {
+			defer wg.Done()
+			found := registry.register(owner, "", map[string][]string{"azurerm_storage_account": {"stprdapp"}})
+			mu.Lock()
+			duplicates += len(found)
+			mu.Unlock()
+		}(randSeq(16, nil))
+	}
+	wg.Wait()
+	if duplicates != 49 {
+		t.Errorf("Expected every name but the first one to be a duplicate, got %d duplicates", duplicates)
+	}
+}
+
+func TestRegisterNames_OnDuplicateName(t *testing.T) {
+	for _, onDuplicateName := range []string{DuplicateNameWarn, DuplicateNameError, DuplicateNameIgnore} {
+		t.Run(onDuplicateName, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"on_duplicate_name": onDuplicateName})
+			meta, diags := providerConfigure(context.Background(), d)
+			if diags.HasError() {
+				t.Fatalf("providerConfigure failed: %v", diags)
+			}
+
+			config := map[string]interface{}{
+				"name":          "app",
+				"resource_type": "azurerm_key_vault",
+				"scope_key":     "rg-app",
+			}
+			first := schema.TestResourceDataRaw(t, resourceName().Schema, config)
+			if _, err := getNameResult(first, meta); err != nil {
+				t.Fatalf("getNameResult failed: %v", err)
+			}
+			// Composing the names of the same resource again is not a duplicate
+			if _, err := getNameResult(first, meta); err != nil {
+				t.Fatalf("getNameResult failed for the same resource: %v", err)
+			}
+
+			// Neither is reading the same data source again
+			other := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{"name": "other", "resource_type": "azurerm_key_vault"})
+			for i := 0; i < 2; i++ {
+				if diags, err := getNameReadResult(other, meta); len(diags) != 0 || err != nil {
+					t.Fatalf("Expected no duplicate reading the data source again, got %v and %v", diags, err)
+				}
+			}
+
+			second := schema.TestResourceDataRaw(t, resourceName().Schema, config)
+			diags, err := getNameResult(second, meta)
+			data := schema.TestResourceDataRaw(t, dataName().Schema, config)
+			dataDiags, dataErr := getNameReadResult(data, meta)
+			if onDuplicateName == DuplicateNameError {
+				if err == nil || dataErr == nil {
+					t.Errorf("Expected duplicate errors, got %v and %v", err, dataErr)
+				}
+				if second.Id() != "" {
+					t.Errorf("Expected no ID for a rejected name, got %s", second.Id())
+				}
+				return
+			}
+			if err != nil || dataErr != nil {
+				t.Errorf("Expected duplicates to be allowed, got %v and %v", err, dataErr)
+			}
+
+			// Terraform shows the duplicates as warnings in warn mode
+			for _, diags := range []diag.Diagnostics{diags, dataDiags} {
+				if onDuplicateName == DuplicateNameIgnore {
+					if len(diags) != 0 {
+						t.Errorf("Expected no warning, got %v", diags)
+					}
+					continue
+				}
+				if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, "name kv-app of azurerm_key_vault is generated more than once") {
+					t.Errorf("Expected a duplicate warning, got %v", diags)
+				}
+			}
+		})
+	}
+}
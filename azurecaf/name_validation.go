@@ -0,0 +1,57 @@
+package azurecaf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Naming rules reported by validateName.
+const (
+	ruleMinLength = "min_length"
+	ruleMaxLength = "max_length"
+	ruleLowerCase = "lowercase"
+	rulePattern   = "pattern"
+)
+
+// nameViolation describes a single naming rule broken by a name.
+type nameViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// validateName checks an existing name against the length, case and pattern
+// constraints of a resource definition and returns every rule it breaks.
+// An empty slice means the name is compliant.
+func validateName(resource *ResourceStructure, name string) ([]nameViolation, error) {
+	validationRegEx, err := compileRegexp(resource.ValidationRegExp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validation regex for resource type '%s': %w", resource.ResourceTypeName, err)
+	}
+
+	violations := []nameViolation{}
+	if len(name) < resource.MinLength {
+		violations = append(violations, nameViolation{
+			Rule:    ruleMinLength,
+			Message: fmt.Sprintf("name '%s' is %d characters long, the minimum for %s is %d", name, len(name), resource.ResourceTypeName, resource.MinLength),
+		})
+	}
+	if len(name) > resource.MaxLength {
+		violations = append(violations, nameViolation{
+			Rule:    ruleMaxLength,
+			Message: fmt.Sprintf("name '%s' is %d characters long, the maximum for %s is %d", name, len(name), resource.ResourceTypeName, resource.MaxLength),
+		})
+	}
+	if resource.LowerCase && strings.ToLower(name) != name {
+		violations = append(violations, nameViolation{
+			Rule:    ruleLowerCase,
+			Message: fmt.Sprintf("name '%s' must be lowercase for %s", name, resource.ResourceTypeName),
+		})
+	}
+	if !validationRegEx.MatchString(name) {
+		violations = append(violations, nameViolation{
+			Rule:    rulePattern,
+			Message: fmt.Sprintf("name '%s' does not match the pattern %s for %s", name, resource.ValidationRegExp, resource.ResourceTypeName),
+		})
+	}
+	return violations, nil
+}
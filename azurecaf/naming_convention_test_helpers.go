@@ -68,8 +68,8 @@ func runNamingConventionTest(t *testing.T, testCase NamingConventionTestCase) {
 		}
 	}
 
-	// Validate against Azure naming requirements if Resources map exists
-	if resource, exists := Resources[testCase.ResourceType]; exists && resource.ValidationRegExp != "" {
+	// Validate against Azure naming requirements of the resource definition
+	if resource, err := getLegacyResource(testCase.ResourceType); err == nil && resource.ValidationRegExp != "" {
 		if !regexp.MustCompile(resource.ValidationRegExp).MatchString(result) {
 			t.Errorf("Result '%s' does not match Azure naming requirements for %s", result, testCase.ResourceType)
 		}
@@ -113,7 +113,7 @@ func validateNamingConventionResult(t *testing.T, result, resourceType, conventi
 	}
 
 	// Validate against Azure naming requirements
-	if resource, exists := Resources[resourceType]; exists && resource.ValidationRegExp != "" {
+	if resource, err := getLegacyResource(resourceType); err == nil && resource.ValidationRegExp != "" {
 		if !regexp.MustCompile(resource.ValidationRegExp).MatchString(result) {
 			t.Errorf("Result '%s' does not match Azure naming requirements for %s", result, resourceType)
 		}
@@ -0,0 +1,212 @@
+package azurecaf
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NamingPolicySchema is the JSON Schema published for naming policy files.
+//
+//go:embed naming_policy.schema.json
+var NamingPolicySchema []byte
+
+// Name components that can be ordered by a policy template.
+const (
+	componentPrefixes = "prefixes"
+	componentSlug     = "slug"
+	componentName     = "name"
+	componentRandom   = "random"
+	componentSuffixes = "suffixes"
+)
+
+// defaultPolicyTemplate is the component order of getResourceName.
+var defaultPolicyTemplate = []string{componentPrefixes, componentSlug, componentName, componentRandom, componentSuffixes}
+
+// namingPolicy is a naming standard loaded from the provider policy_file. It is
+// applied to the name components before they go through getResourceName. Its
+// abbreviations are replaced with abbreviate, see dictionariesFromMeta.
+type namingPolicy struct {
+	Version              int                 `json:"version"`
+	Separator            string              `json:"separator"`
+	Templates            map[string][]string `json:"templates"`
+	Abbreviations        map[string]string   `json:"abbreviations"`
+	Slugs                map[string]string   `json:"slugs"`
+	AllowedResourceTypes []string            `json:"allowed_resource_types"`
+	RequiredComponents   []string            `json:"required_components"`
+}
+
+// loadNamingPolicy reads a YAML or JSON policy file and validates it against
+// NamingPolicySchema and the resource definitions.
+func loadNamingPolicy(path string) (*namingPolicy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the naming policy: %w", err)
+	}
+	return parseNamingPolicy(path, content)
+}
+
+func parseNamingPolicy(path string, content []byte) (*namingPolicy, error) {
+	// YAML documents are converted to JSON so both formats are validated the same way
+	if extension := strings.ToLower(filepath.Ext(path)); extension == ".yaml" || extension == ".yml" {
+		var document interface{}
+		if err := yaml.Unmarshal(content, &document); err != nil {
+			return nil, fmt.Errorf("unable to parse the naming policy %s: %w", path, err)
+		}
+		var err error
+		if content, err = json.Marshal(document); err != nil {
+			return nil, fmt.Errorf("unable to parse the naming policy %s: %w", path, err)
+		}
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("unable to parse the naming policy %s: %w", path, err)
+	}
+	schema, err := parseJSONSchema(NamingPolicySchema)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := schema.validate(document)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		return nil, fmt.Errorf("the naming policy %s does not match its schema:\n%s", path, strings.Join(violations, "\n"))
+	}
+
+	var policy namingPolicy
+	if err := json.Unmarshal(content, &policy); err != nil {
+		return nil, fmt.Errorf("unable to parse the naming policy %s: %w", path, err)
+	}
+	if err := policy.validateResourceTypes(); err != nil {
+		return nil, fmt.Errorf("invalid naming policy %s: %w", path, err)
+	}
+	abbreviations := make(map[string]string, len(policy.Abbreviations))
+	for word, abbreviation := range policy.Abbreviations {
+		abbreviations[strings.ToLower(word)] = abbreviation
+	}
+	policy.Abbreviations = abbreviations
+	return &policy, nil
+}
+
+func (p *namingPolicy) validateResourceTypes() error {
+	resourceTypes := []string{}
+	for resourceType := range p.Templates {
+		if resourceType != "default" {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+	}
+	for resourceType := range p.Slugs {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	resourceTypes = append(resourceTypes, p.AllowedResourceTypes...)
+
+	errorStrings := []string{}
+	for _, resourceType := range resourceTypes {
+		if _, found := ResourceDefinitions[resourceType]; !found {
+			errorStrings = append(errorStrings, fmt.Sprintf("unknown resource type %s", resourceType))
+		}
+	}
+	if len(errorStrings) > 0 {
+		return fmt.Errorf("%s", strings.Join(errorStrings, ", "))
+	}
+	return nil
+}
+
+// apply checks the policy constraints for resourceType and arranges the components in
+// the order of its template. Components placed before the name are handed to
+// getResourceName as prefixes and components placed after it as suffixes.
+func (p *namingPolicy) apply(resourceType string, inputs nameInputs) (nameInputs, error) {
+	if len(p.AllowedResourceTypes) > 0 && !containsString(p.AllowedResourceTypes, resourceType) {
+		return inputs, fmt.Errorf("resource type %s is not allowed by the naming policy", resourceType)
+	}
+	for _, component := range p.RequiredComponents {
+		missing := false
+		switch component {
+		case componentPrefixes:
+			missing = len(inputs.Prefixes) == 0
+		case componentName:
+			missing = inputs.Name == ""
+		case componentRandom:
+			missing = inputs.Random == ""
+		case componentSuffixes:
+			missing = len(inputs.Suffixes) == 0
+		}
+		if missing {
+			return inputs, fmt.Errorf("the naming policy requires %s for %s", component, resourceType)
+		}
+	}
+
+	if p.Separator != "" {
+		inputs.Separator = p.Separator
+	}
+
+	slug := ""
+	if inputs.UseSlug {
+		slug = getSlug(resourceType, ConventionCafClassic)
+		if override, found := p.Slugs[resourceType]; found {
+			slug = override
+		}
+	}
+
+	template := p.Templates[resourceType]
+	if template == nil {
+		template = p.Templates["default"]
+	}
+	if template == nil {
+		template = defaultPolicyTemplate
+	}
+
+	provided := map[string]bool{
+		componentPrefixes: len(inputs.Prefixes) > 0,
+		componentRandom:   inputs.Random != "",
+		componentSuffixes: len(inputs.Suffixes) > 0,
+	}
+	for _, component := range []string{componentPrefixes, componentRandom, componentSuffixes} {
+		if provided[component] && !containsString(template, component) {
+			return inputs, fmt.Errorf("the naming policy template of %s has no place for %s", resourceType, component)
+		}
+	}
+
+	before, after := []string{}, []string{}
+	current := &before
+	for _, component := range template {
+		switch component {
+		case componentPrefixes:
+			*current = append(*current, inputs.Prefixes...)
+		case componentSlug:
+			if slug != "" {
+				*current = append(*current, slug)
+			}
+		case componentName:
+			current = &after
+		case componentRandom:
+			if inputs.Random != "" {
+				*current = append(*current, inputs.Random)
+			}
+		case componentSuffixes:
+			*current = append(*current, inputs.Suffixes...)
+		}
+	}
+
+	inputs.Prefixes = before
+	inputs.Suffixes = after
+	inputs.Random = ""
+	inputs.UseSlug = false
+	return inputs, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
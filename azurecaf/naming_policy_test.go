@@ -0,0 +1,182 @@
+package azurecaf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const testNamingPolicyYAML = `
+version: 1
+separator: "-"
+templates:
+  default: [prefixes, slug, name, suffixes, random]
+  azurerm_storage_account: [slug, name, random]
+abbreviations:
+  Production: prd
+  development: dev
+  kubernetes: k8s
+slugs:
+  azurerm_resource_group: rsg
+allowed_resource_types:
+  - azurerm_resource_group
+  - azurerm_storage_account
+  - azurerm_key_vault
+required_components: [name]
+`
+
+func TestParseNamingPolicy(t *testing.T) {
+	policy, err := parseNamingPolicy("policy.yaml", []byte(testNamingPolicyYAML))
+	if err != nil {
+		t.Fatalf("parseNamingPolicy failed: %v", err)
+	}
+	if policy.Slugs["azurerm_resource_group"] != "rsg" || policy.Abbreviations["production"] != "prd" {
+		t.Errorf("Unexpected policy %+v", policy)
+	}
+
+	if _, err := parseNamingPolicy("policy.json", []byte(`{"version": 1, "templates": {"default": ["slug", "name"]}}`)); err != nil {
+		t.Errorf("Expected a valid JSON policy, got %v", err)
+	}
+}
+
+func TestParseNamingPolicy_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		path     string
+		content  string
+		expected string
+	}{
+		"invalid yaml":          {"policy.yml", "version: [", "unable to parse"},
+		"missing version":       {"policy.json", `{}`, "/: missing required property version"},
+		"unknown property":      {"policy.json", `{"version": 1, "prefix": "x"}`, "unknown property prefix"},
+		"template without name": {"policy.json", `{"version": 1, "templates": {"default": ["slug"]}}`, "/templates/default: must contain"},
+		"unknown component":     {"policy.json", `{"version": 1, "templates": {"default": ["name", "region"]}}`, "/templates/default/1: must be one of"},
+		"duplicated component":  {"policy.json", `{"version": 1, "templates": {"default": ["name", "name"]}}`, "identical"},
+		"unknown resource type": {"policy.json", `{"version": 1, "slugs": {"azurerm_not_covered": "x"}}`, "unknown resource type azurerm_not_covered"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseNamingPolicy(tt.path, []byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestNamingPolicy_Names(t *testing.T) {
+	policy, err := parseNamingPolicy("policy.yaml", []byte(testNamingPolicyYAML))
+	if err != nil {
+		t.Fatalf("parseNamingPolicy failed: %v", err)
+	}
+
+	// The policy abbreviations are replaced with abbreviate, like the environments
+	params := nameParameters{
+		Name:          "myapp",
+		Prefixes:      []string{"Production"},
+		Suffixes:      []string{"web-development"},
+		Separator:     "_",
+		ResourceType:  "azurerm_resource_group",
+		ResourceTypes: []string{"azurerm_key_vault"},
+		CleanInput:    true,
+		UseSlug:       true,
+		RandomString:  "xvlbz",
+		RandomLength:  5,
+		Policy:        policy,
+	}
+	if result, _, err := generateNames(params); err != nil || result != "Production-rsg-myapp-web-development-xvlbz" {
+		t.Errorf("Expected no abbreviation without abbreviate, got %s (%v)", result, err)
+	}
+	params.Abbreviate = true
+	params.Environments, params.Abbreviations = dictionariesFromMeta(&providerConfiguration{Policy: policy})
+	params.Replacements = map[string]string{}
+	params.Name = "kubernetes"
+	result, results, err := generateNames(params)
+	if err != nil {
+		t.Fatalf("generateNames failed: %v", err)
+	}
+	if result != "prd-rsg-k8s-web-dev-xvlbz" {
+		t.Errorf("Unexpected resource group name %s", result)
+	}
+	if params.Replacements["kubernetes"] != "k8s" || params.Replacements["Production"] != "prd" {
+		t.Errorf("Expected the replacements to be reported, got %v", params.Replacements)
+	}
+	// Key vault names are limited to 24 characters, the abbreviated prefixes fit
+	if results["azurerm_key_vault"] != "prd-kv-k8s-web-dev-xvlbz" {
+		t.Errorf("Unexpected key vault name %s", results["azurerm_key_vault"])
+	}
+
+	// The storage account template has no place for prefixes
+	if _, _, err := generateNames(nameParameters{Name: "myapp", Prefixes: []string{"prd"}, ResourceType: "azurerm_storage_account", CleanInput: true, UseSlug: true, Policy: policy}); err == nil || !strings.Contains(err.Error(), "no place for prefixes") {
+		t.Errorf("Expected a template error, got %v", err)
+	}
+	if _, _, err := generateNames(nameParameters{Name: "myapp", ResourceType: "azurerm_virtual_network", UseSlug: true, Policy: policy}); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("Expected a resource type error, got %v", err)
+	}
+	if _, _, err := generateNames(nameParameters{ResourceType: "azurerm_resource_group", UseSlug: true, Policy: policy}); err == nil || !strings.Contains(err.Error(), "requires name") {
+		t.Errorf("Expected a required component error, got %v", err)
+	}
+}
+
+func TestProviderConfigure_PolicyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "naming.yaml")
+	if err := os.WriteFile(path, []byte(testNamingPolicyYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"policy_file": path})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("providerConfigure failed: %v", diags)
+	}
+
+	rd := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":          "myapp",
+		"resource_type": "azurerm_resource_group",
+	})
+	if _, err := getNameResult(rd, meta); err != nil {
+		t.Fatalf("getNameResult failed: %v", err)
+	}
+	if result := rd.Get("result").(string); result != "rsg-myapp" {
+		t.Errorf("Expected the policy slug, got %s", result)
+	}
+
+	// The policy abbreviations are opt-in and reported like the other abbreviations
+	for _, abbreviate := range []bool{false, true} {
+		rd := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+			"name":          "kubernetes",
+			"resource_type": "azurerm_resource_group",
+			"abbreviate":    abbreviate,
+		})
+		if _, err := getNameResult(rd, meta); err != nil {
+			t.Fatalf("getNameResult failed: %v", err)
+		}
+		expected, replaced := "rsg-kubernetes", map[string]interface{}{}
+		if abbreviate {
+			expected, replaced = "rsg-k8s", map[string]interface{}{"kubernetes": "k8s"}
+		}
+		if result := rd.Get("result").(string); result != expected {
+			t.Errorf("Expected %s with abbreviate %t, got %s", expected, abbreviate, result)
+		}
+		if abbreviations := rd.Get("abbreviations").(map[string]interface{}); !reflect.DeepEqual(abbreviations, replaced) {
+			t.Errorf("Expected the abbreviations %v, got %v", replaced, abbreviations)
+		}
+	}
+
+	data := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{
+		"name":          "myapp",
+		"resource_type": "azurerm_virtual_network",
+	})
+	if diags := dataNameRead(context.Background(), data, meta); !diags.HasError() {
+		t.Error("Expected the data source to enforce the allowed resource types")
+	}
+
+	d = schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"policy_file": filepath.Join(t.TempDir(), "missing.yaml")})
+	if _, diags := providerConfigure(context.Background(), d); !diags.HasError() {
+		t.Error("Expected an error for a missing policy file")
+	}
+}
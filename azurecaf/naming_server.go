@@ -0,0 +1,259 @@
+package azurecaf
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// maxRequestBodySize caps the size of the JSON documents accepted by the naming service.
+const maxRequestBodySize = 1 << 20
+
+//go:embed naming_server_openapi.json
+var namingServerOpenAPI []byte
+
+// NewNamingServer returns an http.Handler exposing the naming pipeline as a JSON API
+// for tooling that cannot run Terraform (portal add-ins, ticketing workflows, etc.).
+//
+// Routes:
+//   - POST /generate: generate names, same inputs as the azurecaf_name resource
+//   - POST /validate: check an existing name against a resource definition
+//   - GET /definitions: list every resource definition
+//   - GET /definitions/{type}: return a single resource definition (type or slug)
+//   - GET /openapi.json: OpenAPI 3 description of the API
+//
+// Names are produced by the same code path as azurecaf_name, so the service and
+// the provider always agree.
+func NewNamingServer() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /generate", handleGenerate)
+	mux.HandleFunc("POST /validate", handleValidate)
+	mux.HandleFunc("GET /definitions", handleListDefinitions)
+	mux.HandleFunc("GET /definitions/{type}", handleGetDefinition)
+	mux.HandleFunc("GET /openapi.json", handleOpenAPI)
+	return mux
+}
+
+// generateRequest is the body of POST /generate. Optional booleans and the separator
+// are pointers so that omitted fields get the same defaults as the Terraform schema.
+type generateRequest struct {
+	Name          string   `json:"name"`
+	Prefixes      []string `json:"prefixes"`
+	Suffixes      []string `json:"suffixes"`
+	Separator     *string  `json:"separator"`
+	ResourceType  string   `json:"resource_type"`
+	ResourceTypes []string `json:"resource_types"`
+	RandomLength  int      `json:"random_length"`
+	RandomSeed    int64    `json:"random_seed"`
+	CleanInput    *bool    `json:"clean_input"`
+	Passthrough   bool     `json:"passthrough"`
+	UseSlug       *bool    `json:"use_slug"`
+}
+
+type generateResponse struct {
+	Result  string            `json:"result,omitempty"`
+	Results map[string]string `json:"results"`
+}
+
+type validateRequest struct {
+	ResourceType string `json:"resource_type"`
+	Name         string `json:"name"`
+}
+
+type validateResponse struct {
+	ResourceType string          `json:"resource_type"`
+	Name         string          `json:"name"`
+	Valid        bool            `json:"valid"`
+	Violations   []nameViolation `json:"violations"`
+}
+
+type definitionResponse struct {
+	ResourceType     string `json:"resource_type"`
+	Slug             string `json:"slug"`
+	MinLength        int    `json:"min_length"`
+	MaxLength        int    `json:"max_length"`
+	LowerCase        bool   `json:"lowercase"`
+	RegEx            string `json:"regex"`
+	ValidationRegExp string `json:"validation_regex"`
+	Dashes           bool   `json:"dashes"`
+	Scope            string `json:"scope"`
+}
+
+type errorResponse struct {
+	Error   string   `json:"error"`
+	Details []string `json:"details,omitempty"`
+}
+
+// validate mirrors the schema validation applied by Terraform before the request
+// reaches the naming pipeline.
+func (r *generateRequest) validate() []string {
+	problems := []string{}
+	if r.ResourceType == "" && len(r.ResourceTypes) == 0 {
+		problems = append(problems, "resource_type or resource_types must be set")
+	}
+	if r.ResourceType != "" {
+		if _, err := getResource(r.ResourceType); err != nil {
+			problems = append(problems, fmt.Sprintf("resource_type: %s", err))
+		}
+	}
+	for i, resourceType := range r.ResourceTypes {
+		if _, err := getResource(resourceType); err != nil {
+			problems = append(problems, fmt.Sprintf("resource_types[%d]: %s", i, err))
+		}
+	}
+	if r.RandomLength < 0 {
+		problems = append(problems, fmt.Sprintf("random_length must be non-negative, got: %d", r.RandomLength))
+	}
+	for i, prefix := range r.Prefixes {
+		if prefix == "" {
+			problems = append(problems, fmt.Sprintf("prefixes[%d] must not be empty", i))
+		}
+	}
+	for i, suffix := range r.Suffixes {
+		if suffix == "" {
+			problems = append(problems, fmt.Sprintf("suffixes[%d] must not be empty", i))
+		}
+	}
+	return problems
+}
+
+func (r *generateRequest) parameters() nameParameters {
+	params := nameParameters{
+		Name:          r.Name,
+		Prefixes:      r.Prefixes,
+		Suffixes:      r.Suffixes,
+		Separator:     "-",
+		ResourceType:  r.ResourceType,
+		ResourceTypes: r.ResourceTypes,
+		CleanInput:    true,
+		Passthrough:   r.Passthrough,
+		UseSlug:       true,
+		RandomLength:  r.RandomLength,
+		RandomSeed:    r.RandomSeed,
+	}
+	if r.Separator != nil {
+		params.Separator = *r.Separator
+	}
+	if r.CleanInput != nil {
+		params.CleanInput = *r.CleanInput
+	}
+	if r.UseSlug != nil {
+		params.UseSlug = *r.UseSlug
+	}
+	return params
+}
+
+func handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var request generateRequest
+	if err := decodeJSONBody(w, r, &request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	if problems := request.validate(); len(problems) > 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid request", Details: problems})
+		return
+	}
+
+	result, results, err := generateNames(request.parameters())
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, generateResponse{Result: result, Results: results})
+}
+
+func handleValidate(w http.ResponseWriter, r *http.Request) {
+	var request validateRequest
+	if err := decodeJSONBody(w, r, &request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	if request.ResourceType == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid request", Details: []string{"resource_type must be set"}})
+		return
+	}
+	resource, err := getResource(request.ResourceType)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid request", Details: []string{fmt.Sprintf("resource_type: %s", err)}})
+		return
+	}
+
+	violations, err := validateName(resource, request.Name)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, validateResponse{
+		ResourceType: resource.ResourceTypeName,
+		Name:         request.Name,
+		Valid:        len(violations) == 0,
+		Violations:   violations,
+	})
+}
+
+func handleListDefinitions(w http.ResponseWriter, r *http.Request) {
+	definitions := make([]definitionResponse, 0, len(ResourceDefinitions))
+	for _, resource := range ResourceDefinitions {
+		definitions = append(definitions, newDefinitionResponse(&resource))
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].ResourceType < definitions[j].ResourceType
+	})
+	writeJSON(w, http.StatusOK, definitions)
+}
+
+func handleGetDefinition(w http.ResponseWriter, r *http.Request) {
+	resource, err := getResource(r.PathValue("type"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, newDefinitionResponse(resource))
+}
+
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(namingServerOpenAPI)
+}
+
+func newDefinitionResponse(resource *ResourceStructure) definitionResponse {
+	return definitionResponse{
+		ResourceType:     resource.ResourceTypeName,
+		Slug:             resource.CafPrefix,
+		MinLength:        resource.MinLength,
+		MaxLength:        resource.MaxLength,
+		LowerCase:        resource.LowerCase,
+		RegEx:            resource.RegEx,
+		ValidationRegExp: resource.ValidationRegExp,
+		Dashes:           resource.Dashes,
+		Scope:            resource.Scope,
+	}
+}
+
+// decodeJSONBody decodes a single JSON document, rejecting unknown fields so that
+// typos in optional arguments are reported instead of silently ignored.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, target interface{}) error {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" && !strings.HasPrefix(contentType, "application/json") {
+		return fmt.Errorf("unsupported content type %s, expected application/json", contentType)
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if decoder.More() {
+		return errors.New("invalid JSON body: unexpected data after the JSON document")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,197 @@
+package azurecaf
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func doNamingServerRequest(t *testing.T, server *httptest.Server, method, path, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	buf := new(bytes.Buffer)
+	buf.ReadFrom(resp.Body)
+	return resp, buf.Bytes()
+}
+
+func TestNamingServer_Generate(t *testing.T) {
+	server := httptest.NewServer(NewNamingServer())
+	defer server.Close()
+
+	body := `{"name":"myrg","resource_type":"azurerm_resource_group","prefixes":["a","b"],"resource_types":["azurerm_storage_account"]}`
+	resp, payload := doNamingServerRequest(t, server, http.MethodPost, "/generate", body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, payload)
+	}
+
+	var response generateResponse
+	if err := json.Unmarshal(payload, &response); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+
+	// The service must produce exactly what the provider pipeline produces
+	expected, expectedResults, err := generateNames(nameParameters{
+		Name:          "myrg",
+		Prefixes:      []string{"a", "b"},
+		Separator:     "-",
+		ResourceType:  "azurerm_resource_group",
+		ResourceTypes: []string{"azurerm_storage_account"},
+		CleanInput:    true,
+		UseSlug:       true,
+	})
+	if err != nil {
+		t.Fatalf("generateNames failed: %v", err)
+	}
+	if response.Result != expected || response.Result != "a-b-rg-myrg" {
+		t.Errorf("Expected result %s, got %s", expected, response.Result)
+	}
+	if response.Results["azurerm_storage_account"] != expectedResults["azurerm_storage_account"] {
+		t.Errorf("Expected storage account name %s, got %s", expectedResults["azurerm_storage_account"], response.Results["azurerm_storage_account"])
+	}
+}
+
+func TestNamingServer_GenerateRequestValidation(t *testing.T) {
+	server := httptest.NewServer(NewNamingServer())
+	defer server.Close()
+
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"malformed json", `{"name":`, http.StatusBadRequest},
+		{"unknown field", `{"name":"a","resource_type":"azurerm_resource_group","unknown":true}`, http.StatusBadRequest},
+		{"missing resource type", `{"name":"a"}`, http.StatusBadRequest},
+		{"unsupported resource type", `{"name":"a","resource_type":"azurerm_does_not_exist"}`, http.StatusBadRequest},
+		{"negative random length", `{"name":"a","resource_type":"azurerm_resource_group","random_length":-1}`, http.StatusBadRequest},
+		{"empty prefix", `{"name":"a","resource_type":"azurerm_resource_group","prefixes":[""]}`, http.StatusBadRequest},
+		{"random length too long", `{"name":"a","resource_type":"azurerm_storage_account","random_length":30}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, payload := doNamingServerRequest(t, server, http.MethodPost, "/generate", tc.body)
+			if resp.StatusCode != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tc.expectedStatus, resp.StatusCode, payload)
+			}
+			var response errorResponse
+			if err := json.Unmarshal(payload, &response); err != nil || response.Error == "" {
+				t.Errorf("Expected an error document, got %s", payload)
+			}
+		})
+	}
+}
+
+func TestNamingServer_Validate(t *testing.T) {
+	server := httptest.NewServer(NewNamingServer())
+	defer server.Close()
+
+	resp, payload := doNamingServerRequest(t, server, http.MethodPost, "/validate", `{"resource_type":"azurerm_storage_account","name":"stmyapp001"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, payload)
+	}
+	var valid validateResponse
+	json.Unmarshal(payload, &valid)
+	if !valid.Valid || len(valid.Violations) != 0 {
+		t.Errorf("Expected stmyapp001 to be valid, got %s", payload)
+	}
+
+	resp, payload = doNamingServerRequest(t, server, http.MethodPost, "/validate", `{"resource_type":"st","name":"St-My-App-001-Which-Is-Far-Too-Long"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, payload)
+	}
+	var invalid validateResponse
+	json.Unmarshal(payload, &invalid)
+	if invalid.Valid {
+		t.Errorf("Expected name to be invalid, got %s", payload)
+	}
+	rules := map[string]bool{}
+	for _, violation := range invalid.Violations {
+		rules[violation.Rule] = true
+	}
+	for _, rule := range []string{ruleMaxLength, ruleLowerCase, rulePattern} {
+		if !rules[rule] {
+			t.Errorf("Expected a %s violation, got %s", rule, payload)
+		}
+	}
+	if invalid.ResourceType != "azurerm_storage_account" {
+		t.Errorf("Expected the slug to resolve to azurerm_storage_account, got %s", invalid.ResourceType)
+	}
+
+	resp, _ = doNamingServerRequest(t, server, http.MethodPost, "/validate", `{"name":"stmyapp001"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a missing resource type, got %d", resp.StatusCode)
+	}
+}
+
+func TestNamingServer_Definitions(t *testing.T) {
+	server := httptest.NewServer(NewNamingServer())
+	defer server.Close()
+
+	resp, payload := doNamingServerRequest(t, server, http.MethodGet, "/definitions", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var definitions []definitionResponse
+	if err := json.Unmarshal(payload, &definitions); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(definitions) != len(ResourceDefinitions) {
+		t.Errorf("Expected %d definitions, got %d", len(ResourceDefinitions), len(definitions))
+	}
+
+	resp, payload = doNamingServerRequest(t, server, http.MethodGet, "/definitions/azurerm_key_vault", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var definition definitionResponse
+	json.Unmarshal(payload, &definition)
+	if definition.Slug != "kv" || definition.MaxLength != 24 {
+		t.Errorf("Unexpected key vault definition: %s", payload)
+	}
+
+	resp, _ = doNamingServerRequest(t, server, http.MethodGet, "/definitions/azurerm_does_not_exist", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.StatusCode)
+	}
+
+	resp, _ = doNamingServerRequest(t, server, http.MethodPost, "/definitions", "")
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", resp.StatusCode)
+	}
+}
+
+func TestNamingServer_OpenAPI(t *testing.T) {
+	server := httptest.NewServer(NewNamingServer())
+	defer server.Close()
+
+	resp, payload := doNamingServerRequest(t, server, http.MethodGet, "/openapi.json", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	var document struct {
+		OpenAPI string                 `json:"openapi"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	if err := json.Unmarshal(payload, &document); err != nil {
+		t.Fatalf("OpenAPI document is not valid JSON: %v", err)
+	}
+	for _, path := range []string{"/generate", "/validate", "/definitions", "/definitions/{type}"} {
+		if _, ok := document.Paths[path]; !ok {
+			t.Errorf("OpenAPI document does not describe %s", path)
+		}
+	}
+}
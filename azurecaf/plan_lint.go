@@ -0,0 +1,224 @@
+package azurecaf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Output formats supported by WriteLintFindings.
+const (
+	LintFormatText  = "text"
+	LintFormatJSON  = "json"
+	LintFormatSARIF = "sarif"
+)
+
+// LintFinding is a naming rule violation found on a resource of a Terraform plan.
+type LintFinding struct {
+	Address      string `json:"address"`
+	ResourceType string `json:"resource_type"`
+	Name         string `json:"name"`
+	Rule         string `json:"rule"`
+	Message      string `json:"message"`
+}
+
+// planDocument is the subset of the `terraform show -json` output needed by the linter.
+// Plans carry the resources under planned_values, state documents under values.
+type planDocument struct {
+	PlannedValues *planValues `json:"planned_values"`
+	Values        *planValues `json:"values"`
+}
+
+type planValues struct {
+	RootModule planModule `json:"root_module"`
+}
+
+type planModule struct {
+	Resources    []planResource `json:"resources"`
+	ChildModules []planModule   `json:"child_modules"`
+}
+
+type planResource struct {
+	Address string                 `json:"address"`
+	Mode    string                 `json:"mode"`
+	Type    string                 `json:"type"`
+	Values  map[string]interface{} `json:"values"`
+}
+
+// LintPlan reads the JSON representation of a plan (or state) produced by
+// `terraform show -json` and checks the name attribute of every managed azurerm_*
+// resource against its definition in ResourceDefinitions. Resources without a
+// definition, and names that are unknown until apply, are skipped.
+func LintPlan(r io.Reader) ([]LintFinding, error) {
+	var document planDocument
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("unable to parse plan JSON: %w", err)
+	}
+
+	values := document.PlannedValues
+	if values == nil {
+		values = document.Values
+	}
+	if values == nil {
+		return nil, fmt.Errorf("unable to find planned_values or values in the plan JSON, generate it with `terraform show -json`")
+	}
+
+	findings := []LintFinding{}
+	err := lintModule(&values.RootModule, func(resource planResource) error {
+		if resource.Mode != "managed" || !strings.HasPrefix(resource.Type, "azurerm_") {
+			return nil
+		}
+		definition, found := ResourceDefinitions[resource.Type]
+		if !found {
+			return nil
+		}
+		name, ok := resource.Values["name"].(string)
+		if !ok {
+			return nil
+		}
+		violations, err := validateName(&definition, name)
+		if err != nil {
+			return err
+		}
+		for _, violation := range violations {
+			findings = append(findings, LintFinding{
+				Address:      resource.Address,
+				ResourceType: resource.Type,
+				Name:         name,
+				Rule:         violation.Rule,
+				Message:      violation.Message,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Address < findings[j].Address
+	})
+	return findings, nil
+}
+
+func lintModule(module *planModule, check func(planResource) error) error {
+	for _, resource := range module.Resources {
+		if err := check(resource); err != nil {
+			return err
+		}
+	}
+	for i := range module.ChildModules {
+		if err := lintModule(&module.ChildModules[i], check); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteLintFindings renders findings in the text, json or sarif format.
+func WriteLintFindings(w io.Writer, format string, findings []LintFinding) error {
+	switch format {
+	case LintFormatText:
+		for _, finding := range findings {
+			if _, err := fmt.Fprintf(w, "%s: [%s] %s\n", finding.Address, finding.Rule, finding.Message); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(w, "%d naming violation(s) found\n", len(findings))
+		return err
+	case LintFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(findings)
+	case LintFormatSARIF:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(newSarifLog(findings))
+	default:
+		return fmt.Errorf("unsupported output format %s, expected one of %s, %s or %s", format, LintFormatText, LintFormatJSON, LintFormatSARIF)
+	}
+}
+
+// Minimal SARIF 2.1.0 model, enough for code scanning uploads.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func newSarifLog(findings []LintFinding) sarifLog {
+	results := make([]sarifResult, 0, len(findings))
+	for _, finding := range findings {
+		results = append(results, sarifResult{
+			RuleID:  finding.Rule,
+			Level:   "error",
+			Message: sarifMessage{Text: fmt.Sprintf("%s: %s", finding.Address, finding.Message)},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					FullyQualifiedName: finding.Address,
+					Kind:               "resource",
+				}},
+			}},
+		})
+	}
+
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "azurecaf-lint",
+				InformationURI: "https://github.com/aztfmod/terraform-provider-azurecaf",
+				Rules: []sarifRule{
+					{ID: ruleMinLength, ShortDescription: sarifMessage{Text: "Name is shorter than the minimum length of the resource type"}},
+					{ID: ruleMaxLength, ShortDescription: sarifMessage{Text: "Name is longer than the maximum length of the resource type"}},
+					{ID: ruleLowerCase, ShortDescription: sarifMessage{Text: "Name must be lowercase for the resource type"}},
+					{ID: rulePattern, ShortDescription: sarifMessage{Text: "Name does not match the validation pattern of the resource type"}},
+				},
+			}},
+			Results: results,
+		}},
+	}
+}
@@ -0,0 +1,156 @@
+package azurecaf
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testPlanLintJSON = `{
+  "format_version": "1.2",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "azurerm_storage_account.compliant",
+          "mode": "managed",
+          "type": "azurerm_storage_account",
+          "name": "compliant",
+          "values": { "name": "stmyapp001" }
+        },
+        {
+          "address": "azurerm_storage_account.invalid",
+          "mode": "managed",
+          "type": "azurerm_storage_account",
+          "name": "invalid",
+          "values": { "name": "St-MyApp-001" }
+        },
+        {
+          "address": "azurerm_resource_group.unknown",
+          "mode": "managed",
+          "type": "azurerm_resource_group",
+          "name": "unknown",
+          "values": {}
+        },
+        {
+          "address": "data.azurerm_key_vault.existing",
+          "mode": "data",
+          "type": "azurerm_key_vault",
+          "name": "existing",
+          "values": { "name": "Not_A_Valid_Key_Vault_Name_At_All" }
+        },
+        {
+          "address": "azurerm_not_covered.example",
+          "mode": "managed",
+          "type": "azurerm_not_covered",
+          "name": "example",
+          "values": { "name": "whatever!" }
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.kv",
+          "resources": [
+            {
+              "address": "module.kv.azurerm_key_vault.this[0]",
+              "mode": "managed",
+              "type": "azurerm_key_vault",
+              "name": "this",
+              "index": 0,
+              "values": { "name": "kv" }
+            }
+          ]
+        }
+      ]
+    }
+  }
+}`
+
+func TestLintPlan(t *testing.T) {
+	findings, err := LintPlan(strings.NewReader(testPlanLintJSON))
+	if err != nil {
+		t.Fatalf("LintPlan failed: %v", err)
+	}
+
+	rulesByAddress := map[string][]string{}
+	for _, finding := range findings {
+		rulesByAddress[finding.Address] = append(rulesByAddress[finding.Address], finding.Rule)
+	}
+
+	expected := map[string][]string{
+		"azurerm_storage_account.invalid":     {ruleLowerCase, rulePattern},
+		"module.kv.azurerm_key_vault.this[0]": {ruleMinLength, rulePattern},
+	}
+	if len(rulesByAddress) != len(expected) {
+		t.Fatalf("Expected findings for %d resources, got %v", len(expected), rulesByAddress)
+	}
+	for address, rules := range expected {
+		if strings.Join(rulesByAddress[address], ",") != strings.Join(rules, ",") {
+			t.Errorf("Expected rules %v for %s, got %v", rules, address, rulesByAddress[address])
+		}
+	}
+}
+
+func TestLintPlan_StateDocument(t *testing.T) {
+	state := `{"values":{"root_module":{"resources":[{"address":"azurerm_storage_account.s","mode":"managed","type":"azurerm_storage_account","values":{"name":"ab"}}]}}}`
+	findings, err := LintPlan(strings.NewReader(state))
+	if err != nil {
+		t.Fatalf("LintPlan failed: %v", err)
+	}
+	if len(findings) == 0 || findings[0].Rule != ruleMinLength {
+		t.Errorf("Expected a min_length finding, got %v", findings)
+	}
+}
+
+func TestLintPlan_InvalidDocument(t *testing.T) {
+	if _, err := LintPlan(strings.NewReader("not json")); err == nil {
+		t.Error("Expected an error for an invalid document")
+	}
+	if _, err := LintPlan(strings.NewReader(`{"format_version":"1.2"}`)); err == nil {
+		t.Error("Expected an error for a document without values")
+	}
+}
+
+func TestWriteLintFindings(t *testing.T) {
+	findings, err := LintPlan(strings.NewReader(testPlanLintJSON))
+	if err != nil {
+		t.Fatalf("LintPlan failed: %v", err)
+	}
+
+	var text bytes.Buffer
+	if err := WriteLintFindings(&text, LintFormatText, findings); err != nil {
+		t.Fatalf("text output failed: %v", err)
+	}
+	if !strings.Contains(text.String(), "azurerm_storage_account.invalid: [lowercase]") || !strings.Contains(text.String(), "4 naming violation(s) found") {
+		t.Errorf("Unexpected text output:\n%s", text.String())
+	}
+
+	var jsonOutput bytes.Buffer
+	if err := WriteLintFindings(&jsonOutput, LintFormatJSON, findings); err != nil {
+		t.Fatalf("json output failed: %v", err)
+	}
+	var decoded []LintFinding
+	if err := json.Unmarshal(jsonOutput.Bytes(), &decoded); err != nil || len(decoded) != len(findings) {
+		t.Errorf("Unexpected json output: %s", jsonOutput.String())
+	}
+
+	var sarif bytes.Buffer
+	if err := WriteLintFindings(&sarif, LintFormatSARIF, findings); err != nil {
+		t.Fatalf("sarif output failed: %v", err)
+	}
+	var report sarifLog
+	if err := json.Unmarshal(sarif.Bytes(), &report); err != nil {
+		t.Fatalf("sarif output is not valid JSON: %v", err)
+	}
+	if report.Version != "2.1.0" || len(report.Runs) != 1 || len(report.Runs[0].Results) != len(findings) {
+		t.Errorf("Unexpected sarif output: %s", sarif.String())
+	}
+	if name := report.Runs[0].Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName; name != findings[0].Address {
+		t.Errorf("Expected location %s, got %s", findings[0].Address, name)
+	}
+
+	if err := WriteLintFindings(&bytes.Buffer{}, "xml", findings); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
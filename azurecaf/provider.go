@@ -8,6 +8,8 @@
 //   - azurecaf_name resource: Creates names with full validation and customization options
 //   - azurecaf_naming_convention resource: Legacy naming convention resource (deprecated)
 //   - azurecaf_name data source: Generates names during plan phase for early validation
+//   - azurecaf_name_components data source: Decomposes an existing name into its components
+//   - azurecaf_location data source: Returns the standard short code of an Azure region
 //   - azurecaf_environment_variable data source: Retrieves environment variables
 //
 // The provider supports multiple naming conventions including CAF classic, CAF random,
@@ -16,6 +18,7 @@ package azurecaf
 
 import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Provider returns the configured Terraform provider schema with all supported
@@ -28,24 +31,89 @@ import (
 // Data Sources:
 //   - azurecaf_environment_variable: Retrieves environment variables with validation
 //   - azurecaf_name: Generates names during plan phase for early validation
+//   - azurecaf_name_components: Decomposes an existing name into prefixes, slug, name and suffixes
+//   - azurecaf_location: Returns the short code, paired region and geography of an Azure region
 //
 // The provider requires no configuration parameters and works out-of-the-box with
-// the built-in Azure resource definitions.
+// the built-in Azure resource definitions. Named conventions can optionally be
+// defined with convention blocks and selected per azurecaf_name, a naming policy
+// file can be loaded with policy_file, on_duplicate_name decides how names
+// generated twice during the same run are reported, and ledger_file records the
+// names across workspaces. name_availability checks that the names are not taken
+// in Azure.
 func Provider() *schema.Provider {
 	return &schema.Provider{
-		// No provider-level configuration required
-		Schema: map[string]*schema.Schema{},
+		// Optional provider-level configuration
+		Schema: map[string]*schema.Schema{
+			"convention": providerConventionSchema(),
+			"policy_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to a YAML or JSON naming policy applied to every generated name.",
+			},
+			"on_duplicate_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      DuplicateNameWarn,
+				ValidateFunc: validation.StringInSlice([]string{DuplicateNameWarn, DuplicateNameError, DuplicateNameIgnore}, false),
+				Description:  "Behavior when two names generated during the same run collide in the same scope: warn (default), error or ignore.",
+			},
+			"name_availability": providerNameAvailabilitySchema(),
+			"reroll_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultRerollAttempts,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of random parts drawn for a name that is taken, in the ledger or in Azure.",
+			},
+			"environments": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Environment names and their abbreviation, merged with the built-in ones, replaced in the names with abbreviate. An empty abbreviation removes a built-in environment.",
+			},
+			"abbreviations": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Words and their abbreviation, merged with the built-in ones, replaced in the names with abbreviate that are too long. An empty abbreviation removes a built-in word.",
+			},
+			"ledger_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to a JSON ledger shared across workspaces recording the names reserved by azurecaf_name resources.",
+			},
+			"ledger_workspace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_WORKSPACE", "default"),
+				Description: "Workspace owning the names reserved in the ledger. Defaults to the TF_WORKSPACE environment variable, or default.",
+			},
+			"ledger_collision": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      LedgerCollisionReject,
+				ValidateFunc: validation.StringInSlice([]string{LedgerCollisionReject, LedgerCollisionReroll}, false),
+				Description:  "Behavior when a name is already reserved in the ledger: reject (default) or reroll the random part.",
+			},
+		},
+		ConfigureContextFunc: providerConfigure,
 
 		// Resources that can be created and managed
 		ResourcesMap: map[string]*schema.Resource{
 			"azurecaf_naming_convention": resourceNamingConvention(), // Legacy - use azurecaf_name instead
 			"azurecaf_name":              resourceName(),             // Primary naming resource
+			"azurecaf_name_set":          resourceNameSet(),          // Names of a workload sharing their random part
 		},
 
 		// Data sources for retrieving information
 		DataSourcesMap: map[string]*schema.Resource{
 			"azurecaf_environment_variable": dataEnvironmentVariable(), // Environment variable lookup
 			"azurecaf_name":                 dataName(),                // Name generation during plan
+			"azurecaf_name_components":      dataNameComponents(),      // Reverse parsing of existing names
+			"azurecaf_location":             dataLocation(),            // Region short codes and pairs
+			"azurecaf_name_set":             dataNameSet(),             // Names of a workload during plan
+			"azurecaf_names":                dataNames(),               // Batch name generation during plan
 		},
 	}
 }
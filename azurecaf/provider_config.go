@@ -0,0 +1,309 @@
+package azurecaf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Slug placements of a named convention.
+const (
+	SlugPlacementPrefix = "prefix"
+	SlugPlacementSuffix = "suffix"
+	SlugPlacementNone   = "none"
+)
+
+// defaultRerollAttempts is the default number of random parts drawn for names that are taken.
+const defaultRerollAttempts = 10
+
+// Case rules of a named convention.
+const (
+	CasePreserve = "preserve"
+	CaseLower    = "lower"
+	CaseUpper    = "upper"
+)
+
+// providerConfiguration is the meta value shared with resources and data sources.
+type providerConfiguration struct {
+	Conventions map[string]*namingConvention
+	Policy      *namingPolicy
+	// Names records the names generated during the run, see registerNames.
+	Names           *nameRegistry
+	OnDuplicateName string
+	// Ledger records the names reserved across runs and workspaces, if any.
+	Ledger *nameLedger
+	// Availability checks that the names are not taken, if any.
+	Availability nameAvailabilityChecker
+	// RerollAttempts is the number of random parts drawn for names that are taken.
+	RerollAttempts int
+	// Environments and Abbreviations are the dictionaries of the names with abbreviate,
+	// the built-in ones merged with the overrides of the provider block.
+	Environments  map[string]string
+	Abbreviations map[string]string
+}
+
+// namingConvention is a named convention defined in the provider block. Its prefixes
+// and suffixes surround the ones of each name, and its separator, slug placement and
+// case rule replace the arguments of the name.
+type namingConvention struct {
+	Name          string
+	Prefixes      []string
+	Suffixes      []string
+	Separator     string
+	SlugPlacement string
+	Case          string
+}
+
+func providerConventionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: "Named naming conventions that can be selected with the convention argument of azurecaf_name.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+					Description:  "Name of the convention.",
+				},
+				"prefixes": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.NoZeroValues},
+					Description: "Prefixes added before the prefixes of each name.",
+				},
+				"suffixes": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.NoZeroValues},
+					Description: "Suffixes added after the suffixes of each name.",
+				},
+				"separator": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Separator replacing the separator of each name.",
+				},
+				"slug_placement": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      SlugPlacementPrefix,
+					ValidateFunc: validation.StringInSlice([]string{SlugPlacementPrefix, SlugPlacementSuffix, SlugPlacementNone}, false),
+					Description:  "Placement of the resource slug: prefix (default), suffix or none.",
+				},
+				"case": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      CasePreserve,
+					ValidateFunc: validation.StringInSlice([]string{CasePreserve, CaseLower, CaseUpper}, false),
+					Description:  "Case of the generated names: preserve (default), lower or upper.",
+				},
+			},
+		},
+	}
+}
+
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	config := &providerConfiguration{
+		Conventions:     map[string]*namingConvention{},
+		Names:           newNameRegistry(),
+		OnDuplicateName: d.Get("on_duplicate_name").(string),
+		RerollAttempts:  d.Get("reroll_attempts").(int),
+		Environments:    mergeDictionary(defaultEnvironments, d.Get("environments").(map[string]interface{})),
+		Abbreviations:   mergeDictionary(defaultAbbreviations, d.Get("abbreviations").(map[string]interface{})),
+	}
+
+	for _, raw := range d.Get("convention").([]interface{}) {
+		block := raw.(map[string]interface{})
+		convention := &namingConvention{
+			Name:          block["name"].(string),
+			Prefixes:      convertInterfaceToString(block["prefixes"].([]interface{})),
+			Suffixes:      convertInterfaceToString(block["suffixes"].([]interface{})),
+			Separator:     block["separator"].(string),
+			SlugPlacement: block["slug_placement"].(string),
+			Case:          block["case"].(string),
+		}
+		if isBuiltinConvention(convention.Name) {
+			return nil, diag.Errorf("convention %s conflicts with a built-in convention", convention.Name)
+		}
+		if _, found := config.Conventions[convention.Name]; found {
+			return nil, diag.Errorf("convention %s is defined more than once", convention.Name)
+		}
+		config.Conventions[convention.Name] = convention
+	}
+
+	if path := d.Get("ledger_file").(string); path != "" {
+		config.Ledger = &nameLedger{
+			Path:      path,
+			Workspace: d.Get("ledger_workspace").(string),
+			Collision: d.Get("ledger_collision").(string),
+		}
+	}
+
+	for _, raw := range d.Get("name_availability").([]interface{}) {
+		block := raw.(map[string]interface{})
+		tokens, err := availabilityTokenSource(block)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		config.Availability = &httpNameAvailabilityChecker{
+			BaseURL:        block["base_url"].(string),
+			SubscriptionID: block["subscription_id"].(string),
+			Tokens:         tokens,
+		}
+	}
+
+	if path := d.Get("policy_file").(string); path != "" {
+		policy, err := loadNamingPolicy(path)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		config.Policy = policy
+	}
+	return config, nil
+}
+
+// policyFromMeta returns the naming policy of the provider configuration, if any.
+func policyFromMeta(meta interface{}) *namingPolicy {
+	if config, ok := meta.(*providerConfiguration); ok {
+		return config.Policy
+	}
+	return nil
+}
+
+func isBuiltinConvention(name string) bool {
+	switch name {
+	case ConventionCafClassic, ConventionCafRandom, ConventionRandom, ConventionPassThrough:
+		return true
+	}
+	return false
+}
+
+// lookupConvention returns the named convention defined in the provider configuration.
+func lookupConvention(meta interface{}, name string) (*namingConvention, error) {
+	if config, ok := meta.(*providerConfiguration); ok {
+		if convention, found := config.Conventions[name]; found {
+			return convention, nil
+		}
+	}
+	return nil, fmt.Errorf("convention %s is not defined in the provider configuration", name)
+}
+
+// components returns the name components for resourceType once the convention is applied.
+func (c *namingConvention) components(resourceType string, inputs nameInputs) nameInputs {
+	inputs.Prefixes = append(append([]string{}, c.Prefixes...), inputs.Prefixes...)
+	inputs.Suffixes = append(append([]string{}, inputs.Suffixes...), c.Suffixes...)
+	if c.Separator != "" {
+		inputs.Separator = c.Separator
+	}
+
+	switch c.SlugPlacement {
+	case SlugPlacementNone:
+		inputs.UseSlug = false
+	case SlugPlacementSuffix:
+		if slug := getSlug(resourceType, ConventionCafClassic); inputs.UseSlug && slug != "" {
+			inputs.Suffixes = append(inputs.Suffixes, slug)
+		}
+		inputs.UseSlug = false
+	}
+	return inputs
+}
+
+// applyCase converts a generated name to the case of the convention. The lowercase rule
+// of the resource definition takes precedence over an uppercase convention.
+func (c *namingConvention) applyCase(resource *ResourceStructure, name string) (string, error) {
+	switch {
+	case c.Case == CaseLower:
+		name = strings.ToLower(name)
+	case c.Case == CaseUpper && !resource.LowerCase:
+		name = strings.ToUpper(name)
+	default:
+		return name, nil
+	}
+	violations, err := validateName(resource, name)
+	if err != nil {
+		return "", err
+	}
+	if len(violations) > 0 {
+		return "", fmt.Errorf("convention %s: %s", c.Name, violations[0].Message)
+	}
+	return name, nil
+}
+
+// rerollAttemptsFromMeta returns the number of random parts drawn for names that are taken.
+func rerollAttemptsFromMeta(meta interface{}) int {
+	if config, ok := meta.(*providerConfiguration); ok && config.RerollAttempts > 0 {
+		return config.RerollAttempts
+	}
+	return defaultRerollAttempts
+}
+
+// mayRerollNames reports whether the names can get a new random part when they are
+// applied, because they are checked for availability or reserved with reroll.
+func mayRerollNames(meta interface{}) bool {
+	config, ok := meta.(*providerConfiguration)
+	return ok && (config.Availability != nil || (config.Ledger != nil && config.Ledger.Collision == LedgerCollisionReroll))
+}
+
+func providerNameAvailabilitySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Checks that the names of azurecaf_name resources are not taken with the checkNameAvailability operations of Azure.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"base_url": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "https://management.azure.com",
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+					Description:  "Base URL of the Azure Resource Manager API.",
+				},
+				"subscription_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ARM_SUBSCRIPTION_ID", nil),
+					Description: "Subscription of the checkNameAvailability operations. Defaults to the ARM_SUBSCRIPTION_ID environment variable.",
+				},
+				"token": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("AZURECAF_ACCESS_TOKEN", nil),
+					Description: "Bearer token of the requests, not renewed when it expires. Defaults to the AZURECAF_ACCESS_TOKEN environment variable.",
+				},
+				"tenant_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ARM_TENANT_ID", nil),
+					Description: "Tenant of the service principal of client_id. Defaults to the ARM_TENANT_ID environment variable.",
+				},
+				"client_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("ARM_CLIENT_ID", nil),
+					Description: "Service principal whose tokens, renewed as they expire, authorize the requests when token is not set. Defaults to the ARM_CLIENT_ID environment variable.",
+				},
+				"client_secret": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.EnvDefaultFunc("ARM_CLIENT_SECRET", nil),
+					Description: "Secret of the service principal of client_id. Defaults to the ARM_CLIENT_SECRET environment variable.",
+				},
+				"authority_url": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "https://login.microsoftonline.com",
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+					Description:  "Base URL of Microsoft Entra ID, issuing the tokens of client_id.",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package azurecaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func testProviderMeta(t *testing.T, conventions []interface{}) interface{} {
+	t.Helper()
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"convention": conventions})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("providerConfigure failed: %v", diags)
+	}
+	return meta
+}
+
+func TestProviderConfigure_Conventions(t *testing.T) {
+	meta := testProviderMeta(t, []interface{}{
+		map[string]interface{}{"name": "platform", "prefixes": []interface{}{"plt"}, "separator": "_"},
+		map[string]interface{}{"name": "sandbox", "slug_placement": SlugPlacementNone},
+	})
+	platform, err := lookupConvention(meta, "platform")
+	if err != nil {
+		t.Fatalf("lookupConvention failed: %v", err)
+	}
+	if platform.Separator != "_" || platform.SlugPlacement != SlugPlacementPrefix || platform.Case != CasePreserve {
+		t.Errorf("Unexpected convention %+v", platform)
+	}
+	if _, err := lookupConvention(meta, "application"); err == nil {
+		t.Error("Expected an error for an unknown convention")
+	}
+	if _, err := lookupConvention(nil, "platform"); err == nil {
+		t.Error("Expected an error without provider configuration")
+	}
+
+	for name, conventions := range map[string][]interface{}{
+		"duplicate": {map[string]interface{}{"name": "platform"}, map[string]interface{}{"name": "platform"}},
+		"built-in":  {map[string]interface{}{"name": ConventionCafRandom}},
+	} {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"convention": conventions})
+		if _, diags := providerConfigure(context.Background(), d); !diags.HasError() {
+			t.Errorf("Expected an error for a %s convention", name)
+		}
+	}
+}
+
+func TestNamedConvention_Names(t *testing.T) {
+	meta := testProviderMeta(t, []interface{}{
+		map[string]interface{}{"name": "platform", "prefixes": []interface{}{"plt"}, "suffixes": []interface{}{"we"}},
+		map[string]interface{}{"name": "application", "separator": "_", "slug_placement": SlugPlacementSuffix, "case": CaseUpper},
+		map[string]interface{}{"name": "sandbox", "slug_placement": SlugPlacementNone},
+	})
+
+	tests := []struct {
+		convention string
+		expected   map[string]string
+	}{
+		{"platform", map[string]string{"azurerm_resource_group": "plt-dev-rg-myapp-001-we", "azurerm_storage_account": "pltdevstmyapp001we"}},
+		{"application", map[string]string{"azurerm_resource_group": "DEV_MYAPP_001_RG", "azurerm_storage_account": "devmyapp001st"}},
+		{"sandbox", map[string]string{"azurerm_resource_group": "dev-myapp-001", "azurerm_storage_account": "devmyapp001"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.convention, func(t *testing.T) {
+			rd := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+				"name":           "myapp",
+				"prefixes":       []interface{}{"dev"},
+				"suffixes":       []interface{}{"001"},
+				"resource_type":  "azurerm_resource_group",
+				"resource_types": []interface{}{"azurerm_storage_account"},
+				"convention":     tt.convention,
+			})
+			if _, err := getNameResult(rd, meta); err != nil {
+				t.Fatalf("getNameResult failed: %v", err)
+			}
+			if result := rd.Get("result").(string); result != tt.expected["azurerm_resource_group"] {
+				t.Errorf("Expected result %s, got %s", tt.expected["azurerm_resource_group"], result)
+			}
+			if result := rd.Get("results").(map[string]interface{})["azurerm_storage_account"]; result != tt.expected["azurerm_storage_account"] {
+				t.Errorf("Expected storage account %s, got %v", tt.expected["azurerm_storage_account"], result)
+			}
+		})
+	}
+}
+
+func TestNamedConvention_Unknown(t *testing.T) {
+	raw := map[string]interface{}{
+		"name":          "myapp",
+		"resource_type": "azurerm_resource_group",
+		"convention":    "application",
+	}
+	meta := testProviderMeta(t, []interface{}{map[string]interface{}{"name": "platform"}})
+
+	rd := schema.TestResourceDataRaw(t, resourceName().Schema, raw)
+	if _, err := getNameResult(rd, meta); err == nil {
+		t.Error("Expected an error for an unknown convention")
+	}
+	if _, err := resourceName().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), meta); err == nil {
+		t.Error("Expected the plan to fail for an unknown convention")
+	}
+}
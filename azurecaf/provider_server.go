@@ -0,0 +1,159 @@
+package azurecaf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// GRPCProviderServer returns the protocol server of the provider. The server of the
+// plugin SDK rejects every state move; this one also moves azurecaf_naming_convention
+// resources to azurecaf_name, so that moved blocks keep the generated names.
+func GRPCProviderServer() tfprotov5.ProviderServer {
+	return &providerServer{GRPCProviderServer: schema.NewGRPCProviderServer(Provider())}
+}
+
+type providerServer struct {
+	*schema.GRPCProviderServer
+}
+
+func (s *providerServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp, err := s.GRPCProviderServer.GetMetadata(ctx, req)
+	if resp != nil {
+		resp.ServerCapabilities = withMoveResourceState(resp.ServerCapabilities)
+	}
+	return resp, err
+}
+
+func (s *providerServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := s.GRPCProviderServer.GetProviderSchema(ctx, req)
+	if resp != nil {
+		resp.ServerCapabilities = withMoveResourceState(resp.ServerCapabilities)
+	}
+	return resp, err
+}
+
+func withMoveResourceState(capabilities *tfprotov5.ServerCapabilities) *tfprotov5.ServerCapabilities {
+	if capabilities == nil {
+		capabilities = &tfprotov5.ServerCapabilities{}
+	}
+	capabilities.MoveResourceState = true
+	return capabilities
+}
+
+func (s *providerServer) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	if req == nil || req.SourceTypeName != "azurecaf_naming_convention" || req.TargetTypeName != "azurecaf_name" {
+		return s.GRPCProviderServer.MoveResourceState(ctx, req)
+	}
+
+	resp := &tfprotov5.MoveResourceStateResponse{}
+	if req.SourceState == nil || len(req.SourceState.JSON) == 0 {
+		resp.Diagnostics = append(resp.Diagnostics, moveError("The state of the azurecaf_naming_convention resource is empty"))
+		return resp, nil
+	}
+	var source map[string]interface{}
+	if err := json.Unmarshal(req.SourceState.JSON, &source); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, moveError(fmt.Sprintf("Unable to decode the state of the azurecaf_naming_convention resource: %s", err)))
+		return resp, nil
+	}
+
+	d, warnings, err := moveNamingConventionState(source)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, moveError(err.Error()))
+		return resp, nil
+	}
+	for _, warning := range warnings {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{Severity: tfprotov5.DiagnosticSeverityWarning, Summary: "azurecaf_naming_convention move", Detail: warning})
+	}
+
+	state := d.State()
+	state.Attributes["id"] = state.ID
+	stateType := resourceName().CoreConfigSchema().ImpliedType()
+	value, err := state.AttrsAsObjectValue(stateType)
+	if err == nil {
+		var packed []byte
+		if packed, err = msgpack.Marshal(value, stateType); err == nil {
+			resp.TargetState = &tfprotov5.DynamicValue{MsgPack: packed}
+		}
+	}
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, moveError(err.Error()))
+	}
+	return resp, nil
+}
+
+func moveError(detail string) *tfprotov5.Diagnostic {
+	return &tfprotov5.Diagnostic{Severity: tfprotov5.DiagnosticSeverityError, Summary: "Unable to move azurecaf_naming_convention to azurecaf_name", Detail: detail}
+}
+
+// moveNamingConventionState builds the azurecaf_name state of a moved
+// azurecaf_naming_convention resource. The result is kept as is; prefix and postfix
+// become the prefixes and suffixes of the name, and the resource type is resolved
+// through ResourceDefinitions.
+func moveNamingConventionState(source map[string]interface{}) (*schema.ResourceData, []string, error) {
+	stringAttribute := func(key string) string {
+		value, _ := source[key].(string)
+		return value
+	}
+
+	resourceType, err := resolveLegacyResourceType(stringAttribute("resource_type"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if stringAttribute("result") == "" || stringAttribute("id") == "" {
+		return nil, nil, fmt.Errorf("the azurecaf_naming_convention resource has no result to move")
+	}
+	convention := stringAttribute("convention")
+	if convention == "" {
+		convention = ConventionCafRandom
+	}
+	prefixes, suffixes := []string{}, []string{}
+	if prefix := stringAttribute("prefix"); prefix != "" {
+		prefixes = append(prefixes, prefix)
+	}
+	if postfix := stringAttribute("postfix"); postfix != "" {
+		suffixes = append(suffixes, postfix)
+	}
+
+	warnings := []string{}
+	if maxLength, _ := source["max_length"].(float64); maxLength > 0 {
+		warnings = append(warnings, fmt.Sprintf("max_length (%d) has no equivalent in azurecaf_name, the name %s is kept", int(maxLength), stringAttribute("result")))
+	}
+
+	d := resourceName().Data(nil)
+	d.SetId(stringAttribute("id"))
+	d.Set("name", stringAttribute("name"))
+	d.Set("resource_type", resourceType)
+	d.Set("prefixes", prefixes)
+	d.Set("suffixes", suffixes)
+	d.Set("convention", convention)
+	d.Set("separator", "-")
+	d.Set("clean_input", true)
+	d.Set("passthrough", false)
+	d.Set("use_slug", true)
+	d.Set("random_length", 0)
+	if seed, _ := source["random_seed"].(float64); seed != 0 {
+		d.Set("random_seed", int(seed))
+	}
+	d.Set("instance_count", 0)
+	d.Set("instance_start", defaultInstanceStart)
+	d.Set("instance_padding", defaultInstancePadding)
+	d.Set("recreate_on_drift", false)
+	d.Set("drift_detected", false)
+	d.Set("result", stringAttribute("result"))
+	d.Set("results", map[string]string{})
+	d.Set("created_at", clock().UTC().Format(time.RFC3339))
+	params, err := readNameParameters(d, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range derivedNameAttributes(params) {
+		d.Set(key, value)
+	}
+	return d, warnings, nil
+}
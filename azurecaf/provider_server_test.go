@@ -0,0 +1,122 @@
+package azurecaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+func TestProviderServer_MoveResourceState(t *testing.T) {
+	server := GRPCProviderServer()
+
+	schemaResponse, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schemaResponse.ServerCapabilities.MoveResourceState {
+		t.Error("Expected the provider to announce state moves")
+	}
+
+	resp, err := server.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+		SourceTypeName:      "azurecaf_naming_convention",
+		TargetTypeName:      "azurecaf_name",
+		SourceSchemaVersion: 2,
+		SourceState: &tfprotov5.RawState{JSON: []byte(`{
+			"id": "abcdefghijklmnop",
+			"name": "log",
+			"convention": "cafrandom",
+			"prefix": "rdmi",
+			"postfix": "001",
+			"max_length": 20,
+			"resource_type": "st",
+			"result": "rdmistlog001xvlbzgbaic"
+		}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, diagnostic := range resp.Diagnostics {
+		if diagnostic.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("MoveResourceState failed: %s", diagnostic.Detail)
+		}
+	}
+	if len(resp.Diagnostics) != 1 {
+		t.Errorf("Expected a warning for max_length, got %d diagnostics", len(resp.Diagnostics))
+	}
+
+	state, err := msgpack.Unmarshal(resp.TargetState.MsgPack, resourceName().CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	attributes := state.AsValueMap()
+	expected := map[string]string{
+		"id":            "abcdefghijklmnop",
+		"name":          "log",
+		"resource_type": "azurerm_storage_account",
+		"convention":    "cafrandom",
+		"result":        "rdmistlog001xvlbzgbaic",
+		"separator":     "-",
+	}
+	for key, value := range expected {
+		if got := attributes[key].AsString(); got != value {
+			t.Errorf("Expected %s to be %s, got %s", key, value, got)
+		}
+	}
+	if prefixes := attributes["prefixes"].AsValueSlice(); len(prefixes) != 1 || prefixes[0].AsString() != "rdmi" {
+		t.Errorf("Expected the prefix to become the prefixes, got %#v", prefixes)
+	}
+	if suffixes := attributes["suffixes"].AsValueSlice(); len(suffixes) != 1 || suffixes[0].AsString() != "001" {
+		t.Errorf("Expected the postfix to become the suffixes, got %#v", suffixes)
+	}
+	// The defaults are set so that the next plan does not replace the moved resource
+	if start, _ := attributes["instance_start"].AsBigFloat().Int64(); start != defaultInstanceStart {
+		t.Errorf("Expected instance_start to be %d, got %d", defaultInstanceStart, start)
+	}
+	if padding, _ := attributes["instance_padding"].AsBigFloat().Int64(); padding != defaultInstancePadding {
+		t.Errorf("Expected instance_padding to be %d, got %d", defaultInstancePadding, padding)
+	}
+
+	// The next plan of the moved resource changes nothing
+	checkEmptyNamePlan(t, server, state, map[string]cty.Value{
+		"name":          cty.StringVal("log"),
+		"resource_type": cty.StringVal("azurerm_storage_account"),
+		"convention":    cty.StringVal("cafrandom"),
+		"prefixes":      cty.ListVal([]cty.Value{cty.StringVal("rdmi")}),
+		"suffixes":      cty.ListVal([]cty.Value{cty.StringVal("001")}),
+	})
+}
+
+func TestProviderServer_MoveResourceState_Errors(t *testing.T) {
+	server := GRPCProviderServer()
+	tests := map[string]*tfprotov5.MoveResourceStateRequest{
+		"unknown resource type": {
+			SourceTypeName: "azurecaf_naming_convention",
+			TargetTypeName: "azurecaf_name",
+			SourceState:    &tfprotov5.RawState{JSON: []byte(`{"id": "x", "result": "x", "resource_type": "unknown"}`)},
+		},
+		"no result": {
+			SourceTypeName: "azurecaf_naming_convention",
+			TargetTypeName: "azurecaf_name",
+			SourceState:    &tfprotov5.RawState{JSON: []byte(`{"id": "x", "resource_type": "st"}`)},
+		},
+		"unsupported move": {
+			SourceTypeName: "random_string",
+			TargetTypeName: "azurecaf_name",
+			SourceState:    &tfprotov5.RawState{JSON: []byte(`{}`)},
+		},
+	}
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := server.MoveResourceState(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Diagnostics) == 0 || resp.Diagnostics[0].Severity != tfprotov5.DiagnosticSeverityError {
+				t.Errorf("Expected an error diagnostic, got %#v", resp.Diagnostics)
+			}
+		})
+	}
+}
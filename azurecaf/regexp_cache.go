@@ -0,0 +1,39 @@
+package azurecaf
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// compiledRegexps caches the regular expressions of the resource definitions by pattern.
+// Names are composed and validated many times per plan, for large configurations and
+// batches, and a compiled regular expression is safe for concurrent use.
+var compiledRegexps sync.Map
+
+// compileRegexp returns the compiled regular expression of pattern, compiling it once.
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if cached, found := compiledRegexps.Load(pattern); found {
+		return cached.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	compiledRegexps.Store(pattern, compiled)
+	return compiled, nil
+}
+
+// resourceRegexps returns the compiled cleaning and validation regular expressions
+// of resource.
+func resourceRegexps(resource *ResourceStructure) (*regexp.Regexp, *regexp.Regexp, error) {
+	cleanRegEx, err := compileRegexp(resource.RegEx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid cleaning regular expression of %s: %w", resource.ResourceTypeName, err)
+	}
+	validationRegEx, err := compileRegexp(resource.ValidationRegExp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid validation regular expression of %s: %w", resource.ResourceTypeName, err)
+	}
+	return cleanRegEx, validationRegEx, nil
+}
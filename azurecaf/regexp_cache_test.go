@@ -0,0 +1,81 @@
+package azurecaf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileRegexpCache(t *testing.T) {
+	first, err := compileRegexp("^[a-z]+$")
+	if err != nil {
+		t.Fatalf("compileRegexp failed: %v", err)
+	}
+	second, err := compileRegexp("^[a-z]+$")
+	if err != nil {
+		t.Fatalf("compileRegexp failed: %v", err)
+	}
+	if first != second {
+		t.Error("Expected the cached regular expression to be reused")
+	}
+	if _, err := compileRegexp("("); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestResourceRegexpsInvalid(t *testing.T) {
+	invalidClean := &ResourceStructure{ResourceTypeName: "test_clean", RegEx: "(", ValidationRegExp: "^.*$"}
+	if _, err := cleanString("name", invalidClean); err == nil || !strings.Contains(err.Error(), "invalid cleaning regular expression of test_clean") {
+		t.Errorf("Expected a cleaning regular expression error, got %v", err)
+	}
+	if _, err := cleanSlice([]string{"name"}, invalidClean); err == nil {
+		t.Error("Expected cleanSlice to report the error")
+	}
+
+	invalidValidation := &ResourceStructure{ResourceTypeName: "test_validation", RegEx: "[^a-z]", ValidationRegExp: "["}
+	if _, _, err := resourceRegexps(invalidValidation); err == nil || !strings.Contains(err.Error(), "invalid validation regular expression of test_validation") {
+		t.Errorf("Expected a validation regular expression error, got %v", err)
+	}
+}
+
+func TestResourceRegexpsDefinitions(t *testing.T) {
+	for resourceTypeName, resource := range ResourceDefinitions {
+		if _, _, err := resourceRegexps(&resource); err != nil {
+			t.Errorf("Definition of %s: %v", resourceTypeName, err)
+		}
+	}
+}
+
+func BenchmarkGetResourceName(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for resourceTypeName := range ResourceDefinitions {
+			if _, err := getResourceName(resourceTypeName, "-", []string{"dev"}, "myapp", []string{"001"}, "xvlbz", "cafclassic", true, false, true, []string{"name", "slug", "random", "suffixes", "prefixes"}); err != nil {
+				b.Fatalf("getResourceName failed for %s: %v", resourceTypeName, err)
+			}
+		}
+	}
+}
+
+func BenchmarkGetResourceNameParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for resourceTypeName := range ResourceDefinitions {
+				if _, err := getResourceName(resourceTypeName, "-", []string{"dev"}, "myapp", []string{"001"}, "xvlbz", "cafclassic", true, false, true, []string{"name", "slug", "random", "suffixes", "prefixes"}); err != nil {
+					b.Errorf("getResourceName failed for %s: %v", resourceTypeName, err)
+					return
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkCleanString(b *testing.B) {
+	resource := ResourceDefinitions["azurerm_storage_account"]
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := cleanString("My-Storage_Account.001", &resource); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
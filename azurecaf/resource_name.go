@@ -2,10 +2,15 @@ package azurecaf
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"regexp"
+	"log"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -125,6 +130,114 @@ func resourceNameStateUpgradeV2(ctx context.Context, rawState map[string]interfa
 	return rawState, nil
 }
 
+// resourceNameV3 returns the schema of the azurecaf_name resource (version 3), before
+// the instance numbering arguments and their defaults.
+func resourceNameV3() *schema.Resource {
+	resource := resourceNameV2()
+	resource.Schema["use_slug"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		ForceNew: true,
+		Default:  true,
+	}
+	return resource
+}
+
+// resourceNameStateUpgradeV3 sets the defaults of the arguments added since version 3
+// and the computed attributes derived from the arguments, so that states written
+// before them are neither replaced nor updated by the next plan.
+func resourceNameStateUpgradeV3(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	nameSchema := resourceName().Schema
+	for key, attribute := range nameSchema {
+		if rawState[key] == nil && attribute.Default != nil {
+			rawState[key] = attribute.Default
+		}
+	}
+	params, err := readNameParameters(rawNameState{values: rawState, schema: nameSchema}, meta)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range derivedNameAttributes(params) {
+		if rawState[key] == nil {
+			rawState[key] = value
+		}
+	}
+
+	return rawState, nil
+}
+
+// rawNameState reads the arguments of a raw azurecaf_name state, or of a spec of
+// azurecaf_names, like schema.ResourceData, with the default or the zero value of the
+// attributes it lacks.
+type rawNameState struct {
+	values map[string]interface{}
+	schema map[string]*schema.Schema
+}
+
+func (state rawNameState) Get(key string) interface{} {
+	attribute := state.schema[key]
+	value := state.values[key]
+	if value == nil {
+		value = attribute.Default
+	}
+	switch typed := value.(type) {
+	case float64:
+		return int(typed)
+	case json.Number:
+		number, _ := typed.Int64()
+		return int(number)
+	case nil:
+		return attribute.ZeroValue()
+	}
+	return value
+}
+
+// derivedNameAttributes returns the computed attributes of azurecaf_name that only
+// depend on the arguments, for the states written before they were added. The names
+// are not composed again: such states have no instance_count and no abbreviate, so
+// results_list, instances and abbreviations are empty.
+func derivedNameAttributes(params nameParameters) map[string]interface{} {
+	scopes := nameScopes(params)
+	return map[string]interface{}{
+		"separators":    nameSeparators(params),
+		"scope":         scopes[params.ResourceType],
+		"scopes":        scopes,
+		"abbreviations": map[string]string{},
+		"results_list":  []string{},
+		"instances":     map[string]string{},
+
+		"uniqueness_hashes": nameUniquenessHashes(params),
+	}
+}
+
+// setMissingNameAttributes sets the arguments with a default and the computed
+// attributes derived from the arguments when they are null in the state of d, which
+// happens for states written before they were added. The names are not composed
+// again. Arguments that cannot be read anymore are reported by checkNameDrift.
+func setMissingNameAttributes(d *schema.ResourceData, meta interface{}) {
+	state := d.GetRawState()
+	if state.IsNull() || !state.IsKnown() {
+		return
+	}
+	missing := func(key string) bool {
+		return state.Type().HasAttribute(key) && state.GetAttr(key).IsNull()
+	}
+	for key, attribute := range resourceName().Schema {
+		if attribute.Default != nil && missing(key) {
+			d.Set(key, attribute.Default)
+		}
+	}
+	params, err := readNameParameters(d, meta)
+	if err != nil {
+		return
+	}
+	for key, value := range derivedNameAttributes(params) {
+		if missing(key) {
+			d.Set(key, value)
+		}
+	}
+}
+
 func resourceName() *schema.Resource {
 	resourceMapsKeys := make([]string, 0, len(ResourceDefinitions))
 	for k := range ResourceDefinitions {
@@ -132,16 +245,23 @@ func resourceName() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Create:        resourceNameCreate,
-		Read:          schema.Noop,
-		Delete:        schema.RemoveFromState,
-		SchemaVersion: 3,
+		CreateContext: resourceNameCreate,
+		ReadContext:   resourceNameRead,
+		UpdateContext: resourceNameUpdate,
+		Delete:        resourceNameDelete,
+		CustomizeDiff: resourceNameCustomizeDiff,
+		SchemaVersion: 4,
 		StateUpgraders: []schema.StateUpgrader{
 			{
 				Type:    resourceNameV2().CoreConfigSchema().ImpliedType(),
 				Upgrade: resourceNameStateUpgradeV2,
 				Version: 2,
 			},
+			{
+				Type:    resourceNameV3().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceNameStateUpgradeV3,
+				Version: 3,
+			},
 		},
 		Importer: &schema.ResourceImporter{
 			State: resourceNameImport,
@@ -151,7 +271,6 @@ func resourceName() *schema.Resource {
 			"name": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Default:  "",
 			},
 			"prefixes": {
@@ -161,7 +280,6 @@ func resourceName() *schema.Resource {
 					ValidateFunc: validation.NoZeroValues,
 				},
 				Optional: true,
-				ForceNew: true,
 			},
 			"suffixes": {
 				Type: schema.TypeList,
@@ -170,7 +288,6 @@ func resourceName() *schema.Resource {
 					ValidateFunc: validation.NoZeroValues,
 				},
 				Optional: true,
-				ForceNew: true,
 			},
 			"random_length": {
 				Type:         schema.TypeInt,
@@ -193,26 +310,120 @@ func resourceName() *schema.Resource {
 			"separator": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Default:  "-",
 			},
+			// Replacement of a separator the resource type does not allow
+			"separator_fallback": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{SeparatorFallbackNone, SeparatorFallbackAuto}, false),
+			},
+			// Separator used for each resource type
+			"separators": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			// Region whose short code is added before the suffixes
+			"location": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateLocation,
+			},
+			// Instance numbers ending the names of results_list
+			"instance_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"instance_start": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultInstanceStart,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"instance_padding": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultInstancePadding,
+				ValidateFunc: validation.IntBetween(1, 10),
+			},
+			// Names of resource_type of each instance, in order and by instance number
+			"results_list": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"instances": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			// Replace the words of the components with the environment dictionary, and with
+			// the abbreviation dictionary when a name is too long
+			"abbreviate": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			// Words replaced by an abbreviation
+			"abbreviations": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			// Hash added to the names of the resource types with a global scope
+			"uniqueness": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{UniquenessNone, UniquenessAuto}, false),
+			},
+			// Added to the input of the uniqueness hash, e.g. a subscription ID
+			"uniqueness_seed": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"scope": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// Key of the scope of the names, e.g. the name of their resource group, used
+			// to detect the names generated more than once during a run
+			"scope_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// Scope of each resource type
+			"scopes": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
 			"clean_input": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				ForceNew: true,
 				Default:  true,
 			},
 			"passthrough": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				ForceNew: true,
 				Default:  false,
 			},
 			"resource_type": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ValidateFunc: validation.StringInSlice(resourceMapsKeys, false),
-				ForceNew:     true,
 			},
 			"resource_types": {
 				Type: schema.TypeList,
@@ -221,7 +432,6 @@ func resourceName() *schema.Resource {
 					ValidateFunc: validation.StringInSlice(resourceMapsKeys, false),
 				},
 				Optional: true,
-				ForceNew: true,
 			},
 			"random_seed": {
 				Type:     schema.TypeInt,
@@ -231,35 +441,346 @@ func resourceName() *schema.Resource {
 			"use_slug": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				ForceNew: true,
 				Default:  true,
 			},
+			// Named convention defined in the provider configuration
+			"convention": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// Arbitrary values that trigger a new random part when they change. Without
+			// keepers, any change of the naming arguments replaces the resource.
+			"keepers": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+			},
+			// Random part of the names, reused when the names are composed again
+			"random_string": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// Uniqueness hashes by resource type, reused when the names are composed again
+			"uniqueness_hashes": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			// Remove the resource from the state when the stored names drifted from
+			// the current resource definitions so that they are generated again
+			"recreate_on_drift": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"drift_detected": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			// Generate the names again when they are older than the given number of days
+			"rotation_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			// Generate the names again once the given RFC 3339 timestamp is reached
+			"rotate_at": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
 
-func resourceNameCreate(d *schema.ResourceData, meta interface{}) error {
-	return resourceNameRead(d, meta)
+func resourceNameCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags, err := getNameResult(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("drift_detected", false)
+	d.Set("created_at", clock().UTC().Format(time.RFC3339))
+	return diags
+}
+
+// resourceNameRead checks the stored names against the current resource definitions.
+// Names are never changed on read: drift is reported as warnings and, when
+// recreate_on_drift is set, the resource is removed from the state to be created again.
+func resourceNameRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	setMissingNameAttributes(d, meta)
+	diags := checkNameDrift(d, meta)
+	d.Set("drift_detected", len(diags) > 0)
+	if len(diags) > 0 && d.Get("recreate_on_drift").(bool) {
+		log.Printf("[WARN] azurecaf_name %s drifted from the resource definitions, removing it from the state", d.Id())
+		d.SetId("")
+	}
+	return diags
+}
+
+// nameArguments are the arguments of azurecaf_name composing the names. When keepers
+// are set, changing them composes the names again with the stored random part.
+var nameArguments = []string{
+	"name",
+	"prefixes",
+	"suffixes",
+	"separator",
+	"clean_input",
+	"passthrough",
+	"resource_type",
+	"resource_types",
+	"use_slug",
+	"convention",
+	"separator_fallback",
+	"uniqueness",
+	"uniqueness_seed",
+	"location",
+	"abbreviate",
+	"instance_count",
+	"instance_start",
+	"instance_padding",
+}
+
+// resourceNameCustomizeDiff decides whether a change replaces the resource. Without
+// keepers, or when keepers change, every naming argument behaves as ForceNew. Otherwise
+// the names are composed again in place, reusing the random part stored in the state.
+func resourceNameCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if name := d.Get("convention").(string); name != "" && !isBuiltinConvention(name) && d.NewValueKnown("convention") {
+		if _, err := lookupConvention(meta, name); err != nil {
+			return err
+		}
+	}
+	if err := checkRotation(d); err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
+	// A due rotation replaces the resource, which generates a new random part
+	due, err := rotationDue(d)
+	if err != nil {
+		return err
+	}
+	if due {
+		if err := d.SetNewComputed("created_at"); err != nil {
+			return err
+		}
+		return d.ForceNew("created_at")
+	}
+
+	if len(d.Get("keepers").(map[string]interface{})) == 0 || d.HasChange("keepers") {
+		for _, key := range append([]string{"keepers"}, nameArguments...) {
+			if d.HasChange(key) {
+				if err := d.ForceNew(key); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if !d.HasChanges(nameArguments...) {
+		return nil
+	}
+	for _, key := range nameArguments {
+		if !d.NewValueKnown(key) {
+			for _, computed := range []string{"result", "results", "separators", "scope", "scopes", "abbreviations", "results_list", "instances", "uniqueness_hashes"} {
+				if err := d.SetNewComputed(computed); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	params, err := readNameParameters(d, meta)
+	if err != nil {
+		return err
+	}
+	params.RandomString = d.Get("random_string").(string)
+	params.Replacements = map[string]string{}
+	params.UniquenessHashes = convertInterfaceToStringMap(d.Get("uniqueness_hashes").(map[string]interface{}))
+	params.Hashes = map[string]string{}
+	result, results, err := generateNames(params)
+	if err != nil {
+		return err
+	}
+	instanceNames, err := generateInstanceNames(params)
+	if err != nil {
+		return err
+	}
+	if err := d.SetNew("separators", nameSeparators(params)); err != nil {
+		return err
+	}
+	if err := d.SetNew("abbreviations", params.Replacements); err != nil {
+		return err
+	}
+	if err := d.SetNew("uniqueness_hashes", params.Hashes); err != nil {
+		return err
+	}
+	if err := d.SetNew("scopes", nameScopes(params)); err != nil {
+		return err
+	}
+	if err := d.SetNew("scope", nameScopes(params)[params.ResourceType]); err != nil {
+		return err
+	}
+	// Names taken elsewhere draw a new random part when they are applied
+	if mayRerollNames(meta) && params.randomPartLength() > 0 {
+		for _, computed := range []string{"result", "results", "random_string", "results_list", "instances"} {
+			if err := d.SetNewComputed(computed); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := d.SetNew("result", result); err != nil {
+		return err
+	}
+	if err := d.SetNew("results_list", instanceNames); err != nil {
+		return err
+	}
+	if err := d.SetNew("instances", nameInstances(params, instanceNames)); err != nil {
+		return err
+	}
+	return d.SetNew("results", results)
+}
+
+// resourceNameUpdate composes the names again when naming arguments changed, which
+// only happens when keepers are set, then checks them like a refresh.
+func resourceNameUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Resources created before rotations were supported start their window now
+	if d.Get("created_at").(string) == "" {
+		d.Set("created_at", clock().UTC().Format(time.RFC3339))
+	}
+	setMissingNameAttributes(d, meta)
+	var diags diag.Diagnostics
+	if d.HasChanges(nameArguments...) {
+		var err error
+		if diags, err = getNameResult(d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	// Drift is only reported here: removing the resource from the state during an
+	// apply fails it, recreate_on_drift acts on the next refresh
+	driftDiags := checkNameDrift(d, meta)
+	d.Set("drift_detected", len(driftDiags) > 0)
+	return append(diags, driftDiags...)
+}
+
+// checkNameDrift returns a warning for every stored name that breaks the current
+// resource definition of its type, or that would not be generated identically anymore.
+// Names with a random part that is not stored in the state are only validated.
+func checkNameDrift(d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var generated string
+	var generatedResults map[string]string
+
+	resourceType := d.Get("resource_type").(string)
+	result := d.Get("result").(string)
+	storedNames := map[string]string{}
+	for resourceTypeName, name := range d.Get("results").(map[string]interface{}) {
+		storedNames[resourceTypeName] = name.(string)
+	}
+	if resourceType != "" && result != "" {
+		storedNames[resourceType] = result
+	}
+
+	resourceTypeNames := make([]string, 0, len(storedNames))
+	for resourceTypeName := range storedNames {
+		resourceTypeNames = append(resourceTypeNames, resourceTypeName)
+	}
+	sort.Strings(resourceTypeNames)
+
+	for _, resourceTypeName := range resourceTypeNames {
+		name := storedNames[resourceTypeName]
+		resource, err := getResource(resourceTypeName)
+		if err != nil {
+			diags = append(diags, driftWarning(resourceTypeName, fmt.Sprintf("Resource type %s is no longer supported", resourceTypeName), err.Error()))
+			continue
+		}
+		violations, err := validateName(resource, name)
+		if err != nil {
+			diags = append(diags, driftWarning(resourceTypeName, fmt.Sprintf("Unable to validate the name of %s", resourceTypeName), err.Error()))
+			continue
+		}
+		for _, violation := range violations {
+			diags = append(diags, driftWarning(resourceTypeName, fmt.Sprintf("Stored name %s is no longer valid", name), violation.Message))
+		}
+	}
+	if len(diags) > 0 {
+		return diags
+	}
+
+	randomString := d.Get("random_string").(string)
+	params, err := readNameParameters(d, meta)
+	if err == nil && len(randomString) != params.randomPartLength() {
+		return diags
+	}
+	if err == nil {
+		params.RandomString = randomString
+		generated, generatedResults, err = generateNames(params)
+	}
+	if err != nil {
+		return append(diags, driftWarning(resourceType, "Stored name can no longer be generated", err.Error()))
+	}
+	if resourceType != "" && result != "" && generated != result {
+		diags = append(diags, driftWarning(resourceType, fmt.Sprintf("Stored name %s would now be generated differently", result),
+			fmt.Sprintf("The current resource definition of %s generates %s", resourceType, generated)))
+	}
+	for _, resourceTypeName := range resourceTypeNames {
+		name, generatedFound := generatedResults[resourceTypeName]
+		if stored := storedNames[resourceTypeName]; generatedFound && stored != name {
+			diags = append(diags, driftWarning(resourceTypeName, fmt.Sprintf("Stored name %s would now be generated differently", stored),
+				fmt.Sprintf("The current resource definition of %s generates %s", resourceTypeName, name)))
+		}
+	}
+	return diags
 }
 
-func resourceNameRead(d *schema.ResourceData, meta interface{}) error {
-	return getNameResult(d, meta)
+func driftWarning(resourceType string, summary string, detail string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  summary,
+		Detail:   fmt.Sprintf("%s. Set recreate_on_drift to generate the names of %s again.", detail, resourceType),
+	}
 }
 
+// resourceNameDelete releases the names of the resource in the name ledger, if any,
+// and removes the resource from the state.
 func resourceNameDelete(d *schema.ResourceData, meta interface{}) error {
+	if ledger := ledgerFromMeta(meta); ledger != nil && d.Id() != "" {
+		if err := ledger.release(d.Id()); err != nil {
+			return err
+		}
+	}
+	d.SetId("")
 	return nil
 }
 
 // resourceNameImport handles importing existing resource names.
-// Import ID format: <resource_type>:<existing_name>
-// Example: azurerm_storage_account:mystorageaccount123
+// Import ID formats:
+//   - <resource_type>:<existing_name>, example: azurerm_storage_account:mystorageaccount123
+//   - <resource_type>:<existing_name>:<separator>, to decompose the name into its components
+//   - a JSON object describing every argument, see nameImportID
 func resourceNameImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	importID := d.Id()
+	if strings.HasPrefix(strings.TrimSpace(importID), "{") {
+		return resourceNameImportJSON(d, meta, importID)
+	}
 
-	// Parse the import ID
+	// Parse the import ID, the optional third part is the separator used to
+	// decompose the name into its components
 	parts := strings.Split(importID, ":")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid import ID format, expected '<resource_type>:<existing_name>', got: %s", importID)
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, fmt.Errorf("invalid import ID format, expected '<resource_type>:<existing_name>' or '<resource_type>:<existing_name>:<separator>', got: %s", importID)
 	}
 
 	resourceType := parts[0]
@@ -272,7 +793,7 @@ func resourceNameImport(d *schema.ResourceData, meta interface{}) ([]*schema.Res
 	}
 
 	// Validate the existing name against Azure naming rules for this resource type
-	validationRegEx, err := regexp.Compile(resource.ValidationRegExp)
+	validationRegEx, err := compileRegexp(resource.ValidationRegExp)
 	if err != nil {
 		return nil, fmt.Errorf("invalid validation regex for resource type '%s': %w", resourceType, err)
 	}
@@ -282,20 +803,55 @@ func resourceNameImport(d *schema.ResourceData, meta interface{}) ([]*schema.Res
 			existingName, resourceType, resource.ValidationRegExp)
 	}
 
-	// Set the resource data for the imported resource
-	// We use passthrough mode to preserve the existing name as-is
-	d.Set("name", existingName)
+	// The arguments the import ID does not set take their default, like in the
+	// configuration, so that the next plan neither replaces nor updates the resource
+	for key, attribute := range resourceName().Schema {
+		if attribute.Default != nil {
+			d.Set(key, attribute.Default)
+		}
+	}
 	d.Set("resource_type", resourceType)
-	d.Set("passthrough", true)
-
-	// Set empty slices for prefixes and suffixes since we can't reverse-engineer them
-	d.Set("prefixes", []string{})
-	d.Set("suffixes", []string{})
 	d.Set("resource_types", []string{})
+	d.Set("random_string", "")
+	d.Set("drift_detected", false)
+
+	// Decompose the name when a separator is given, the random part cannot be
+	// generated again so it is kept as the first suffix
+	parsed := parsedName{Confidence: NameConfidenceNone}
+	if len(parts) == 3 {
+		if parsed, err = parseName(existingName, resourceType, parts[2], 0); err != nil {
+			return nil, err
+		}
+	}
+
+	if parsed.Confidence == NameConfidenceHigh || parsed.Confidence == NameConfidenceMedium {
+		d.Set("name", parsed.Name)
+		d.Set("prefixes", parsed.Prefixes)
+		d.Set("suffixes", parsed.Suffixes)
+		d.Set("separator", parsed.Separator)
+		d.Set("use_slug", parsed.UseSlug)
+		d.Set("passthrough", false)
+	} else {
+		// We use passthrough mode to preserve the existing name as-is
+		d.Set("name", existingName)
+		d.Set("passthrough", true)
+		d.Set("prefixes", []string{})
+		d.Set("suffixes", []string{})
+	}
 
 	// Set the result to match the imported name
 	d.Set("result", existingName)
 	d.Set("results", map[string]string{})
+	params, err := readNameParameters(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range derivedNameAttributes(params) {
+		d.Set(key, value)
+	}
+
+	// Rotations of imported names start from the import
+	d.Set("created_at", clock().UTC().Format(time.RFC3339))
 
 	// Use the existing name as the Terraform resource ID
 	d.SetId(existingName)
@@ -303,16 +859,203 @@ func resourceNameImport(d *schema.ResourceData, meta interface{}) ([]*schema.Res
 	return []*schema.ResourceData{d}, nil
 }
 
-func cleanSlice(names []string, resourceDefinition *ResourceStructure) []string {
+// nameImportID is the structured import ID of the azurecaf_name resource. It carries
+// the arguments of the configuration so that the imported state matches it exactly.
+type nameImportID struct {
+	Name         string   `json:"name"`
+	ResourceType string   `json:"resource_type"`
+	Result       string   `json:"result"`
+	Prefixes     []string `json:"prefixes"`
+	Suffixes     []string `json:"suffixes"`
+	Separator    *string  `json:"separator"`
+	CleanInput   *bool    `json:"clean_input"`
+	Passthrough  bool     `json:"passthrough"`
+	UseSlug      *bool    `json:"use_slug"`
+	RandomString string   `json:"random_string"`
+	RandomSeed   int64    `json:"random_seed"`
+	Convention   string   `json:"convention"`
+	// RandomLength defaults to the length of RandomString. It is required when the
+	// random part is truncated in the names or only generated from RandomSeed.
+	RandomLength *int `json:"random_length"`
+	// SeparatorFallback is none or auto.
+	SeparatorFallback string `json:"separator_fallback"`
+	// Uniqueness is none or auto.
+	Uniqueness     string `json:"uniqueness"`
+	UniquenessSeed string `json:"uniqueness_seed"`
+	// Location is the region whose short code is added before the suffixes.
+	Location string `json:"location"`
+	// Abbreviate replaces the words of the components with their abbreviation.
+	Abbreviate bool `json:"abbreviate"`
+	// InstanceCount names numbered from InstanceStart, padded to InstancePadding digits.
+	InstanceCount   int               `json:"instance_count"`
+	InstanceStart   *int              `json:"instance_start"`
+	InstancePadding *int              `json:"instance_padding"`
+	Keepers         map[string]string `json:"keepers"`
+	// ResourceTypes lists the additional resource types as "<type>" or
+	// "<type>=<existing_name>" to check the name generated for that type.
+	ResourceTypes []string `json:"resource_types"`
+}
+
+// resourceNameImportJSON imports a resource from a JSON import ID. The names are
+// generated again from the given components and random part, and compared with the
+// existing names of the import ID, so that the next plan does not replace the resource.
+func resourceNameImportJSON(d *schema.ResourceData, meta interface{}, importID string) ([]*schema.ResourceData, error) {
+	decoder := json.NewDecoder(strings.NewReader(importID))
+	decoder.DisallowUnknownFields()
+	var id nameImportID
+	if err := decoder.Decode(&id); err != nil {
+		return nil, fmt.Errorf("invalid import ID format, unable to decode JSON: %w", err)
+	}
+	if id.RandomString != "" && !isRandomPart(id.RandomString, len(id.RandomString)) {
+		return nil, fmt.Errorf("invalid import ID format, random_string must only contain lowercase letters, got: %s", id.RandomString)
+	}
+	if id.RandomLength != nil && (*id.RandomLength < 0 || *id.RandomLength < len(id.RandomString)) {
+		return nil, fmt.Errorf("invalid import ID format, random_length must be at least the length of random_string, got: %d", *id.RandomLength)
+	}
+
+	params := nameParameters{
+		Name:         id.Name,
+		Prefixes:     id.Prefixes,
+		Suffixes:     id.Suffixes,
+		Separator:    "-",
+		ResourceType: id.ResourceType,
+		CleanInput:   true,
+		Passthrough:  id.Passthrough,
+		UseSlug:      true,
+		RandomLength: len(id.RandomString),
+		RandomSeed:   id.RandomSeed,
+		RandomString: id.RandomString,
+
+		SeparatorFallback: id.SeparatorFallback,
+		Uniqueness:        id.Uniqueness,
+		UniquenessSeed:    id.UniquenessSeed,
+		Location:          id.Location,
+		Abbreviate:        id.Abbreviate,
+		Replacements:      map[string]string{},
+		Hashes:            map[string]string{},
+		InstanceStart:     defaultInstanceStart,
+		InstanceCount:     id.InstanceCount,
+		InstancePadding:   defaultInstancePadding,
+	}
+	if id.RandomLength != nil {
+		params.RandomLength = *id.RandomLength
+	}
+	if id.InstanceStart != nil {
+		params.InstanceStart = *id.InstanceStart
+	}
+	if id.InstancePadding != nil {
+		params.InstancePadding = *id.InstancePadding
+	}
+	params.Environments, params.Abbreviations = dictionariesFromMeta(meta)
+	if id.Separator != nil {
+		params.Separator = *id.Separator
+	}
+	if id.CleanInput != nil {
+		params.CleanInput = *id.CleanInput
+	}
+	if id.UseSlug != nil {
+		params.UseSlug = *id.UseSlug
+	}
+	params.Policy = policyFromMeta(meta)
+	if err := params.setConvention(meta, id.Convention); err != nil {
+		return nil, err
+	}
+	// The random part of cafrandom and random names is sized by the resource types
+	if params.fillsRandom() {
+		params.RandomLength = 0
+	}
+	// Without random_string, the random part is generated again from random_seed
+	if params.RandomString == "" && params.randomPartLength() > 0 {
+		params.RandomString = randSeq(params.randomPartLength(), &params.RandomSeed)
+	}
+	existingNames := map[string]string{}
+	for _, pair := range id.ResourceTypes {
+		resourceType, existingName, _ := strings.Cut(pair, "=")
+		params.ResourceTypes = append(params.ResourceTypes, resourceType)
+		if existingName != "" {
+			existingNames[resourceType] = existingName
+		}
+	}
+
+	// getResourceName cleans prefixes and suffixes in place, keep the configured values for the state
+	prefixes := append([]string{}, params.Prefixes...)
+	suffixes := append([]string{}, params.Suffixes...)
+	result, results, err := generateNames(params)
+	if err != nil {
+		return nil, err
+	}
+	instanceNames, err := generateInstanceNames(params)
+	if err != nil {
+		return nil, err
+	}
+	if id.Result != "" && id.Result != result {
+		return nil, fmt.Errorf("the import ID components generate '%s' for %s, expected '%s'", result, id.ResourceType, id.Result)
+	}
+	for resourceType, existingName := range existingNames {
+		if results[resourceType] != existingName {
+			return nil, fmt.Errorf("the import ID components generate '%s' for %s, expected '%s'", results[resourceType], resourceType, existingName)
+		}
+	}
+
+	d.Set("name", params.Name)
+	d.Set("resource_type", params.ResourceType)
+	d.Set("resource_types", params.ResourceTypes)
+	d.Set("prefixes", prefixes)
+	d.Set("suffixes", suffixes)
+	d.Set("separator", params.Separator)
+	d.Set("clean_input", params.CleanInput)
+	d.Set("passthrough", id.Passthrough)
+	d.Set("use_slug", params.UseSlug)
+	d.Set("random_length", params.RandomLength)
+	d.Set("random_string", params.RandomString)
+	d.Set("keepers", id.Keepers)
+	d.Set("convention", id.Convention)
+	d.Set("separator_fallback", id.SeparatorFallback)
+	d.Set("uniqueness", id.Uniqueness)
+	d.Set("uniqueness_seed", id.UniquenessSeed)
+	d.Set("location", id.Location)
+	d.Set("abbreviate", id.Abbreviate)
+	d.Set("instance_count", params.InstanceCount)
+	d.Set("instance_start", params.InstanceStart)
+	d.Set("instance_padding", params.InstancePadding)
+	if params.RandomSeed != 0 {
+		d.Set("random_seed", int(params.RandomSeed))
+	}
+	d.Set("result", result)
+	d.Set("results", results)
+	d.Set("results_list", instanceNames)
+	d.Set("instances", nameInstances(params, instanceNames))
+	d.Set("separators", nameSeparators(params))
+	d.Set("abbreviations", params.Replacements)
+	d.Set("uniqueness_hashes", params.Hashes)
+	setNameScopes(d, params)
+	d.Set("created_at", clock().UTC().Format(time.RFC3339))
+
+	if result != "" {
+		d.SetId(result)
+	} else {
+		d.SetId(randSeq(16, nil))
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func cleanSlice(names []string, resourceDefinition *ResourceStructure) ([]string, error) {
 	for i, name := range names {
-		names[i] = cleanString(name, resourceDefinition)
+		cleaned, err := cleanString(name, resourceDefinition)
+		if err != nil {
+			return nil, err
+		}
+		names[i] = cleaned
 	}
-	return names
+	return names, nil
 }
 
-func cleanString(name string, resourceDefinition *ResourceStructure) string {
-	myRegex, _ := regexp.Compile(resourceDefinition.RegEx)
-	return myRegex.ReplaceAllString(name, "")
+func cleanString(name string, resourceDefinition *ResourceStructure) (string, error) {
+	myRegex, _, err := resourceRegexps(resourceDefinition)
+	if err != nil {
+		return "", err
+	}
+	return myRegex.ReplaceAllString(name, ""), nil
 }
 
 func concatenateParameters(separator string, parameters ...[]string) string {
@@ -332,6 +1075,9 @@ func getResource(resourceType string) (*ResourceStructure, error) {
 		resourceType = resourceKey
 	}
 	if resource, resourceFound := ResourceDefinitions[resourceType]; resourceFound {
+		if _, _, err := resourceRegexps(&resource); err != nil {
+			return nil, err
+		}
 		return &resource, nil
 	}
 	return nil, fmt.Errorf("invalid resource type %s", resourceType)
@@ -365,6 +1111,14 @@ func convertInterfaceToString(source []interface{}) []string {
 	return s
 }
 
+func convertInterfaceToStringMap(source map[string]interface{}) map[string]string {
+	m := make(map[string]string, len(source))
+	for k, v := range source {
+		m[k] = fmt.Sprint(v)
+	}
+	return m
+}
+
 func composeName(separator string,
 	prefixes []string,
 	name string,
@@ -471,34 +1225,74 @@ func getResourceName(resourceTypeName string, separator string,
 	useSlug bool,
 	namePrecedence []string) (string, error) {
 
+	slug := ""
+	if useSlug {
+		slug = getSlug(resourceTypeName, convention)
+	}
+	return composeResourceName(resourceTypeName, separator, prefixes, name, slug, suffixes, randomSuffix, "", cleanInput, passthrough, false, namePrecedence)
+}
+
+// composeResourceName composes, cleans and validates a name once the slug is known.
+// With camelCase, the components are joined without separator and each one but the
+// first starts with an uppercase letter. The instance, if any, ends the name and is
+// never dropped: the other components share the room it leaves.
+func composeResourceName(resourceTypeName string, separator string,
+	prefixes []string,
+	name string,
+	slug string,
+	suffixes []string,
+	randomSuffix string,
+	instance string,
+	cleanInput bool,
+	passthrough bool,
+	camelCase bool,
+	namePrecedence []string) (string, error) {
+
 	resource, err := getResource(resourceTypeName)
 	if err != nil {
 		return "", err
 	}
-	validationRegEx, err := regexp.Compile(resource.ValidationRegExp)
+	_, validationRegEx, err := resourceRegexps(resource)
 	if err != nil {
 		return "", err
 	}
 
-	slug := ""
-	if useSlug {
-		slug = getSlug(resourceTypeName, convention)
-	}
-
 	if cleanInput {
-		prefixes = cleanSlice(prefixes, resource)
-		suffixes = cleanSlice(suffixes, resource)
-		name = cleanString(name, resource)
-		separator = cleanString(separator, resource)
-		randomSuffix = cleanString(randomSuffix, resource)
+		if prefixes, err = cleanSlice(prefixes, resource); err != nil {
+			return "", err
+		}
+		if suffixes, err = cleanSlice(suffixes, resource); err != nil {
+			return "", err
+		}
+		for _, component := range []*string{&name, &separator, &randomSuffix, &instance} {
+			if *component, err = cleanString(*component, resource); err != nil {
+				return "", err
+			}
+		}
+	}
+	if camelCase {
+		separator = ""
+	}
+	maxLength := resource.MaxLength
+	if instance != "" && !passthrough {
+		maxLength -= len(separator) + len(instance)
+		if maxLength < 0 {
+			return "", fmt.Errorf("instance %s does not fit in the name of %s, limited to %d characters", instance, resource.ResourceTypeName, resource.MaxLength)
+		}
 	}
 
 	var resourceName string
 
 	if passthrough {
 		resourceName = name
+	} else if camelCase {
+		resourceName = composeName("", capitalizeAll(prefixes), capitalize(name), capitalize(slug), capitalizeAll(suffixes), capitalize(randomSuffix), maxLength, namePrecedence)
+		resourceName = uncapitalize(resourceName)
 	} else {
-		resourceName = composeName(separator, prefixes, name, slug, suffixes, randomSuffix, resource.MaxLength, namePrecedence)
+		resourceName = composeName(separator, prefixes, name, slug, suffixes, randomSuffix, maxLength, namePrecedence)
+	}
+	if instance != "" && !passthrough {
+		resourceName = concatenateParameters(separator, []string{resourceName, instance})
 	}
 	resourceName = trimResourceName(resourceName, resource.MaxLength)
 
@@ -513,60 +1307,411 @@ func getResourceName(resourceTypeName string, separator string,
 	return resourceName, nil
 }
 
-func getNameResult(d *schema.ResourceData, meta interface{}) error {
-	name := d.Get("name").(string)
-	prefixes := convertInterfaceToString(d.Get("prefixes").([]interface{}))
-	suffixes := convertInterfaceToString(d.Get("suffixes").([]interface{}))
-	separator := d.Get("separator").(string)
-	resourceType := d.Get("resource_type").(string)
-	resourceTypes := convertInterfaceToString(d.Get("resource_types").([]interface{}))
-	cleanInput := d.Get("clean_input").(bool)
-	passthrough := d.Get("passthrough").(bool)
-	useSlug := d.Get("use_slug").(bool)
-	randomLength := d.Get("random_length").(int)
-	randomSeed := int64(d.Get("random_seed").(int))
+// nameParameters holds the inputs of the naming pipeline. It is shared by the
+// azurecaf_name resource, the azurecaf_name data source and the naming service
+// so that every entry point produces identical names for identical inputs.
+type nameParameters struct {
+	Name          string
+	Prefixes      []string
+	Suffixes      []string
+	Separator     string
+	ResourceType  string
+	ResourceTypes []string
+	CleanInput    bool
+	Passthrough   bool
+	UseSlug       bool
+	RandomLength  int
+	RandomSeed    int64
+	// RandomString, when set, is used as the random part instead of generating
+	// RandomLength characters. It allows rebuilding the result of an existing name.
+	RandomString string
+	// Convention is the named convention from the provider configuration, if any.
+	Convention *namingConvention
+	// BuiltinConvention is cafclassic, cafrandom, random or passthrough. Empty
+	// behaves like cafclassic.
+	BuiltinConvention string
+	// SeparatorFallback is none (default) or auto, see fallbackSeparator.
+	SeparatorFallback string
+	// Uniqueness is none (default) or auto, see uniquenessHash.
+	Uniqueness string
+	// UniquenessSeed is added to the input of the uniqueness hash.
+	UniquenessSeed string
+	// Location is a region of LocationDefinitions, its short code is added before
+	// the suffixes.
+	Location string
+	// Abbreviate replaces the words of the components found in Environments, and
+	// the ones found in Abbreviations when the name is too long.
+	Abbreviate    bool
+	Environments  map[string]string
+	Abbreviations map[string]string
+	// Replacements, when not nil, receives the words replaced by an abbreviation.
+	Replacements map[string]string
+	// UniquenessHashes are the uniqueness hashes stored in the state by resource
+	// type, reused when the names are composed again, see withUniquenessHash.
+	UniquenessHashes map[string]string
+	// Hashes, when not nil, receives the uniqueness hash of each resource type.
+	Hashes map[string]string
+	// InstanceCount names are generated by generateInstanceNames, numbered from
+	// InstanceStart and padded with zeros to InstancePadding digits.
+	InstanceStart   int
+	InstanceCount   int
+	InstancePadding int
+	// Instance is the instance number ending the names, see generateInstanceNames.
+	Instance string
+	// Slug, when set, replaces the slug of the resource types.
+	Slug string
+	// Policy is the naming policy loaded from the provider policy_file, if any.
+	Policy *namingPolicy
+}
+
+// nameInputs are the components handed to getResourceName for one resource type,
+// once the named convention and the naming policy are applied.
+type nameInputs struct {
+	Prefixes  []string
+	Name      string
+	Suffixes  []string
+	Separator string
+	Random    string
+	UseSlug   bool
+	// Instance is the instance number ending the name, if any.
+	Instance string
+	// Hash is the uniqueness hash, before the instance number, if any.
+	Hash string
+}
 
+// generateNames runs the naming pipeline for the primary resource type and for
+// every additional resource type. The returned map holds one entry per element
+// of ResourceTypes.
+func generateNames(params nameParameters) (string, map[string]string, error) {
 	// Validate random_length parameter
-	if randomLength < 0 {
-		return fmt.Errorf("random_length must be non-negative, got: %d", randomLength)
+	if params.RandomLength < 0 {
+		return "", nil, fmt.Errorf("random_length must be non-negative, got: %d", params.RandomLength)
 	}
 
 	// Validate against resource type constraints if resource_type is specified
-	if resourceType != "" {
-		if resource, exists := ResourceDefinitions[resourceType]; exists {
+	if params.ResourceType != "" {
+		if resource, exists := ResourceDefinitions[params.ResourceType]; exists {
 			maxLen := resource.MaxLength
-			if randomLength > maxLen {
-				return fmt.Errorf("random_length (%d) exceeds maximum length for resource type %s (%d)", randomLength, resourceType, maxLen)
+			if params.RandomLength > maxLen {
+				return "", nil, fmt.Errorf("random_length (%d) exceeds maximum length for resource type %s (%d)", params.RandomLength, params.ResourceType, maxLen)
 			}
 		}
 	}
 
+	isValid, err := validateResourceType(params.ResourceType, params.ResourceTypes)
+	if !isValid {
+		return "", nil, err
+	}
+
+	compose := nameComposer(params)
+	result := ""
+	if len(params.ResourceType) > 0 {
+		result, err = compose(params.ResourceType)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	results := make(map[string]string, len(params.ResourceTypes))
+	for _, resourceTypeName := range params.ResourceTypes {
+		results[resourceTypeName], err = compose(resourceTypeName)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return result, results, nil
+}
+
+// nameComposer returns the function composing the name of one resource type from
+// params. All the names it composes share the same random part.
+func nameComposer(params nameParameters) func(resourceTypeName string) (string, error) {
 	convention := ConventionCafClassic
 
-	randomSuffix := randSeq(int(randomLength), &randomSeed)
+	randomSuffix := params.RandomString
+	if randomSuffix == "" {
+		randomSuffix = randSeq(params.randomPartLength(), &params.RandomSeed)
+	}
 	namePrecedence := []string{"name", "slug", "random", "suffixes", "prefixes"}
 
-	isValid, err := validateResourceType(resourceType, resourceTypes)
-	if !isValid {
-		return err
+	composeInputs := func(resourceTypeName string, inputs nameInputs) (string, error) {
+		if params.Abbreviate {
+			inputs = abbreviateInputs(inputs, params.Environments, params.Replacements)
+		}
+		if params.Policy != nil {
+			var err error
+			if inputs, err = params.Policy.apply(resourceTypeName, inputs); err != nil {
+				return "", err
+			}
+		}
+		resource, err := getResource(resourceTypeName)
+		if err != nil {
+			return "", err
+		}
+		slug := ""
+		if inputs.UseSlug {
+			slug = getSlug(resourceTypeName, convention)
+			if params.Slug != "" {
+				slug = params.Slug
+			}
+		}
+		if !params.Passthrough {
+			inputs = withUniquenessHash(resource, params, inputs)
+		}
+		separator := fallbackSeparator(resource, inputs.Separator, params.SeparatorFallback)
+		camelCase := separator == SeparatorCamelCase
+		if camelCase {
+			separator = ""
+		}
+		// The uniqueness hash and the instance number end the name and are never dropped
+		ending := concatenateParameters(separator, []string{inputs.Hash, inputs.Instance})
+		if inputs.Hash != "" && len(ending) > resource.MaxLength {
+			return "", fmt.Errorf("uniqueness hash %s does not fit in the name of %s, limited to %d characters", inputs.Hash, resource.ResourceTypeName, resource.MaxLength)
+		}
+		// Abbreviate long words before composeResourceName drops components to fit
+		if params.Abbreviate && !params.Passthrough {
+			length, err := composedLength(resource, inputs, slug, separator, params.CleanInput, !params.fillsRandom())
+			if err != nil {
+				return "", err
+			}
+			if length > resource.MaxLength {
+				inputs = abbreviateInputs(inputs, params.Abbreviations, params.Replacements)
+			}
+		}
+		name, err := composeResourceName(resourceTypeName, separator, inputs.Prefixes, inputs.Name, slug, inputs.Suffixes, inputs.Random, ending, params.CleanInput, params.Passthrough, camelCase, namePrecedence)
+		if err != nil || params.Convention == nil {
+			return name, err
+		}
+		return params.Convention.applyCase(resource, name)
 	}
 
-	if len(resourceType) > 0 {
-		resourceName, err := getResourceName(resourceType, separator, prefixes, name, suffixes, randomSuffix, convention, cleanInput, passthrough, useSlug, namePrecedence)
+	return func(resourceTypeName string) (string, error) {
+		inputs := nameInputs{
+			Prefixes:  params.Prefixes,
+			Name:      params.Name,
+			Suffixes:  params.Suffixes,
+			Separator: params.Separator,
+			Random:    randomSuffix,
+			UseSlug:   params.UseSlug,
+			Instance:  params.Instance,
+		}
+		if params.Location != "" {
+			location, err := getLocation(params.Location)
+			if err != nil {
+				return "", err
+			}
+			inputs.Suffixes = append([]string{location.ShortCode}, params.Suffixes...)
+		}
+		if params.Convention != nil {
+			inputs = params.Convention.components(resourceTypeName, inputs)
+		}
+		if params.fillsRandom() && !params.Passthrough {
+			return composeRandom(resourceTypeName, params, inputs, composeInputs)
+		}
+		return composeInputs(resourceTypeName, inputs)
+	}
+}
+
+// nameArgumentsGetter is implemented by schema.ResourceData and schema.ResourceDiff.
+type nameArgumentsGetter interface {
+	Get(key string) interface{}
+}
+
+// readNameParameters builds the naming pipeline inputs from the arguments of an
+// azurecaf_name resource, resolving its named convention from the provider meta.
+func readNameParameters(d nameArgumentsGetter, meta interface{}) (nameParameters, error) {
+	params := nameParameters{
+		Name:              d.Get("name").(string),
+		Prefixes:          convertInterfaceToString(d.Get("prefixes").([]interface{})),
+		Suffixes:          convertInterfaceToString(d.Get("suffixes").([]interface{})),
+		Separator:         d.Get("separator").(string),
+		ResourceType:      d.Get("resource_type").(string),
+		ResourceTypes:     convertInterfaceToString(d.Get("resource_types").([]interface{})),
+		CleanInput:        d.Get("clean_input").(bool),
+		Passthrough:       d.Get("passthrough").(bool),
+		UseSlug:           d.Get("use_slug").(bool),
+		RandomLength:      d.Get("random_length").(int),
+		RandomSeed:        int64(d.Get("random_seed").(int)),
+		SeparatorFallback: d.Get("separator_fallback").(string),
+		Uniqueness:        d.Get("uniqueness").(string),
+		UniquenessSeed:    d.Get("uniqueness_seed").(string),
+		Location:          d.Get("location").(string),
+		Abbreviate:        d.Get("abbreviate").(bool),
+		InstanceStart:     d.Get("instance_start").(int),
+		InstanceCount:     d.Get("instance_count").(int),
+		InstancePadding:   d.Get("instance_padding").(int),
+		Policy:            policyFromMeta(meta),
+	}
+	params.Environments, params.Abbreviations = dictionariesFromMeta(meta)
+	err := params.setConvention(meta, d.Get("convention").(string))
+	return params, err
+}
+
+// setConvention selects a built-in convention or resolves a named convention from
+// the provider meta.
+func (params *nameParameters) setConvention(meta interface{}, name string) error {
+	switch {
+	case name == "":
+	case isBuiltinConvention(name):
+		params.BuiltinConvention = name
+		if name == ConventionPassThrough {
+			params.Passthrough = true
+		}
+	default:
+		convention, err := lookupConvention(meta, name)
 		if err != nil {
 			return err
 		}
-		d.Set("result", resourceName)
+		params.Convention = convention
+	}
+	return nil
+}
+
+// getNameResult composes the names of azurecaf_name and sets them in d. The returned
+// warnings report the names generated more than once during the run.
+func getNameResult(d *schema.ResourceData, meta interface{}) (diag.Diagnostics, error) {
+	params, err := readNameParameters(d, meta)
+	if err != nil {
+		return nil, err
 	}
-	resourceNames := make(map[string]string, len(resourceTypes))
-	for _, resourceTypeName := range resourceTypes {
+
+	// Reuse the random part stored in the state when the names are composed again
+	params.RandomString = d.Get("random_string").(string)
+	if len(params.RandomString) != params.randomPartLength() {
+		params.RandomString = randSeq(params.randomPartLength(), &params.RandomSeed)
+	}
+
+	id := d.Id()
+	if id == "" {
+		id = randSeq(16, nil)
+	}
+	params.Replacements = map[string]string{}
+	// Like the random part, the stored uniqueness hashes are reused
+	params.UniquenessHashes = convertInterfaceToStringMap(d.Get("uniqueness_hashes").(map[string]interface{}))
+	params.Hashes = map[string]string{}
+	scopeKey := d.Get("scope_key").(string)
+	ledger := ledgerFromMeta(meta)
+	var result string
+	var results map[string]string
+	var instanceNames []string
+	err = reserveNames(meta, id, scopeKey, &params, storedNames(d), func() (map[string][]string, error) {
 		var err error
-		resourceNames[resourceTypeName], err = getResourceName(resourceTypeName, separator, prefixes, name, suffixes, randomSuffix, convention, cleanInput, passthrough, useSlug, namePrecedence)
+		if result, results, err = generateNames(params); err != nil {
+			return nil, err
+		}
+		if instanceNames, err = generateInstanceNames(params); err != nil {
+			return nil, err
+		}
+		return nameResults(params, result, results, instanceNames), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	diags, err := registerNames(meta, id, scopeKey, nameResults(params, result, results, instanceNames))
+	if err != nil {
+		if ledger != nil && d.Id() == "" {
+			ledger.release(id)
+		}
+		return nil, err
+	}
+	if len(params.ResourceType) > 0 {
+		d.Set("result", result)
+	}
+	d.Set("results", results)
+	d.Set("results_list", instanceNames)
+	d.Set("instances", nameInstances(params, instanceNames))
+	d.Set("separators", nameSeparators(params))
+	d.Set("abbreviations", params.Replacements)
+	setNameScopes(d, params)
+	d.Set("random_string", params.RandomString)
+	d.Set("uniqueness_hashes", params.Hashes)
+	d.SetId(id)
+	return diags, nil
+}
+
+// nameResults returns the names of every resource type of params, the primary one and
+// the instances of results_list included.
+func nameResults(params nameParameters, result string, results map[string]string, instanceNames []string) map[string][]string {
+	names := map[string][]string{}
+	add := func(resourceTypeName string, name string) {
+		for _, existing := range names[resourceTypeName] {
+			if existing == name {
+				return
+			}
+		}
+		names[resourceTypeName] = append(names[resourceTypeName], name)
+	}
+	if params.ResourceType != "" {
+		add(params.ResourceType, result)
+		for _, name := range instanceNames {
+			add(params.ResourceType, name)
+		}
+	}
+	for resourceTypeName, name := range results {
+		add(resourceTypeName, name)
+	}
+	return names
+}
+
+// storedNames returns the names already stored in the state of d, which exist in
+// Azure because the resource created them.
+func storedNames(d *schema.ResourceData) map[string]bool {
+	oldResult, _ := d.GetChange("result")
+	oldResults, _ := d.GetChange("results")
+	oldResultsList, _ := d.GetChange("results_list")
+	stored := map[string]bool{oldResult.(string): true}
+	for _, name := range oldResults.(map[string]interface{}) {
+		stored[name.(string)] = true
+	}
+	for _, name := range oldResultsList.([]interface{}) {
+		stored[name.(string)] = true
+	}
+	return stored
+}
+
+// withoutStoredNames returns names, by resource type, without the ones of stored.
+func withoutStoredNames(names map[string][]string, stored map[string]bool) map[string][]string {
+	filtered := map[string][]string{}
+	for resourceTypeName, resourceNames := range names {
+		for _, name := range resourceNames {
+			if !stored[name] {
+				filtered[resourceTypeName] = append(filtered[resourceTypeName], name)
+			}
+		}
+	}
+	return filtered
+}
+
+// reserveNames generates names, by resource type, with generate until they are
+// available and reserved in the name ledger of the provider meta for owner. Taken
+// names draw another random part of params when they have one, up to
+// reroll_attempts. The stored names are not checked for availability.
+func reserveNames(meta interface{}, owner string, scopeKey string, params *nameParameters, stored map[string]bool, generate func() (map[string][]string, error)) error {
+	ledger := ledgerFromMeta(meta)
+	for attempt := 1; ; attempt++ {
+		names, err := generate()
 		if err != nil {
 			return err
 		}
+		err = checkNamesAvailability(context.Background(), meta, withoutStoredNames(names, stored))
+		if err == nil && ledger != nil {
+			err = ledger.reserve(owner, scopeKey, names)
+		}
+		if err == nil {
+			return nil
+		}
+		var unavailable *nameUnavailableError
+		var collision *ledgerCollisionError
+		reroll := errors.As(err, &unavailable) || (errors.As(err, &collision) && ledger.Collision == LedgerCollisionReroll)
+		if !reroll || params.randomPartLength() == 0 {
+			return err
+		}
+		if attempt >= rerollAttemptsFromMeta(meta) {
+			return fmt.Errorf("the names are still taken after %d random parts: %w", attempt, err)
+		}
+		// Draw another random part, seeded ones move to the next seed to stay reproducible
+		seed := params.RandomSeed
+		if seed != 0 {
+			seed += int64(attempt)
+		}
+		params.RandomString = randSeq(params.randomPartLength(), &seed)
 	}
-	d.Set("results", resourceNames)
-	d.SetId(randSeq(16, nil))
-	return nil
 }
@@ -2,6 +2,8 @@ package azurecaf
 
 import (
 	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
 )
 
 // Test the import functionality with specific unit tests
@@ -97,3 +99,134 @@ func TestResourceNameImportUnit(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceNameImportJSON(t *testing.T) {
+	r := resourceName()
+
+	d := r.TestResourceData()
+	d.SetId(`{
+		"name": "myapp",
+		"resource_type": "azurerm_resource_group",
+		"result": "corp-rg-myapp-xvlbz-001",
+		"prefixes": ["corp"],
+		"suffixes": ["001"],
+		"random_string": "xvlbz",
+		"random_seed": 42,
+		"resource_types": ["azurerm_key_vault=corp-kv-myapp-xvlbz-001", "azurerm_storage_account"]
+	}`)
+
+	result, err := resourceNameImport(d, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	imported := result[0]
+
+	expected := map[string]interface{}{
+		"name":          "myapp",
+		"resource_type": "azurerm_resource_group",
+		"result":        "corp-rg-myapp-xvlbz-001",
+		"separator":     "-",
+		"clean_input":   true,
+		"passthrough":   false,
+		"use_slug":      true,
+		"random_length": 5,
+		"random_seed":   42,
+	}
+	for attribute, value := range expected {
+		if imported.Get(attribute) != value {
+			t.Errorf("Expected %s to be %v, got %v", attribute, value, imported.Get(attribute))
+		}
+	}
+	if prefixes := imported.Get("prefixes").([]interface{}); len(prefixes) != 1 || prefixes[0] != "corp" {
+		t.Errorf("Unexpected prefixes %v", prefixes)
+	}
+	if resourceTypes := imported.Get("resource_types").([]interface{}); len(resourceTypes) != 2 || resourceTypes[1] != "azurerm_storage_account" {
+		t.Errorf("Unexpected resource_types %v", resourceTypes)
+	}
+	results := imported.Get("results").(map[string]interface{})
+	if results["azurerm_key_vault"] != "corp-kv-myapp-xvlbz-001" || results["azurerm_storage_account"] != "corpstmyappxvlbz001" {
+		t.Errorf("Unexpected results %v", results)
+	}
+	if imported.Id() != "corp-rg-myapp-xvlbz-001" {
+		t.Errorf("Unexpected id %s", imported.Id())
+	}
+}
+
+func TestResourceNameImportJSONRandomLength(t *testing.T) {
+	seed := int64(42)
+	random := randSeq(5, &seed)
+
+	// Only the seed is known, the random part is generated again
+	d := resourceName().TestResourceData()
+	d.SetId(`{"name": "myapp", "resource_type": "azurerm_resource_group", "random_seed": 42, "random_length": 5, "result": "rg-myapp-` + random + `"}`)
+	result, err := resourceNameImport(d, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if imported := result[0]; imported.Get("random_length") != 5 || imported.Get("random_string") != random {
+		t.Errorf("Unexpected random_length %v and random_string %v", imported.Get("random_length"), imported.Get("random_string"))
+	}
+
+	// The random part is truncated in the existing name
+	d = resourceName().TestResourceData()
+	d.SetId(`{"name": "myapp", "resource_type": "azurerm_resource_group", "random_string": "xvl", "random_length": 5, "result": "rg-myapp-xvl"}`)
+	result, err = resourceNameImport(d, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if imported := result[0]; imported.Get("random_length") != 5 || imported.Get("random_string") != "xvl" {
+		t.Errorf("Unexpected random_length %v and random_string %v", imported.Get("random_length"), imported.Get("random_string"))
+	}
+}
+
+func TestResourceNameImportJSON_Errors(t *testing.T) {
+	tests := map[string]string{
+		"invalid json":        `{"name": `,
+		"unknown field":       `{"name": "myapp", "resource_type": "azurerm_resource_group", "slug": "rg"}`,
+		"invalid random":      `{"name": "myapp", "resource_type": "azurerm_resource_group", "random_string": "AB1"}`,
+		"short random length": `{"name": "myapp", "resource_type": "azurerm_resource_group", "random_string": "xvlbz", "random_length": 3}`,
+		"unknown type":        `{"name": "myapp", "resource_type": "azurerm_not_covered"}`,
+		"result mismatch":     `{"name": "myapp", "resource_type": "azurerm_resource_group", "result": "rg-otherapp"}`,
+		"resource types name": `{"name": "myapp", "resource_types": ["azurerm_key_vault=kv-otherapp"]}`,
+	}
+
+	for name, importID := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := resourceName().TestResourceData()
+			d.SetId(importID)
+			if _, err := resourceNameImport(d, nil); err == nil {
+				t.Errorf("Expected an error for %s", importID)
+			}
+		})
+	}
+}
+
+func TestResourceNameImport_Plan(t *testing.T) {
+	server := GRPCProviderServer()
+	tests := map[string]struct {
+		importID string
+		config   map[string]cty.Value
+	}{
+		"decomposed": {"azurerm_resource_group:rg-myapp-001:-", map[string]cty.Value{
+			"name":          cty.StringVal("myapp"),
+			"resource_type": cty.StringVal("azurerm_resource_group"),
+			"suffixes":      cty.ListVal([]cty.Value{cty.StringVal("001")}),
+		}},
+		"passthrough": {"azurerm_storage_account:mystorageaccount123", map[string]cty.Value{
+			"name":          cty.StringVal("mystorageaccount123"),
+			"resource_type": cty.StringVal("azurerm_storage_account"),
+			"passthrough":   cty.True,
+		}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := importNameState(t, server, tt.importID)
+			for key, attribute := range resourceName().Schema {
+				if (attribute.Default != nil || attribute.Computed) && state.GetAttr(key).IsNull() && key != "random_seed" {
+					t.Errorf("Expected %s to be set by the import", key)
+				}
+			}
+			checkEmptyNamePlan(t, server, state, tt.config)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package azurecaf
+
+import (
+	"fmt"
+)
+
+// Defaults of the instance numbering of azurecaf_name.
+const (
+	defaultInstanceStart   = 1
+	defaultInstancePadding = 3
+)
+
+// formatInstance returns the instance number padded with zeros to padding digits.
+func formatInstance(number int, padding int) string {
+	return fmt.Sprintf("%0*d", padding, number)
+}
+
+// generateInstanceNames returns the InstanceCount names of the resource type of params,
+// each one ended by its instance number. The instance number is never dropped to fit
+// the resource type, the other components are. All the names share the random part.
+func generateInstanceNames(params nameParameters) ([]string, error) {
+	if params.InstanceCount == 0 {
+		return []string{}, nil
+	}
+	if params.ResourceType == "" {
+		return nil, fmt.Errorf("instance_count requires resource_type")
+	}
+	if params.RandomString == "" {
+		params.RandomString = randSeq(params.randomPartLength(), &params.RandomSeed)
+	}
+	names := make([]string, 0, params.InstanceCount)
+	for number := params.InstanceStart; number < params.InstanceStart+params.InstanceCount; number++ {
+		params.Instance = formatInstance(number, params.InstancePadding)
+		name, err := nameComposer(params)(params.ResourceType)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// nameInstances returns the names of instanceNames by instance number.
+func nameInstances(params nameParameters, instanceNames []string) map[string]string {
+	instances := make(map[string]string, len(instanceNames))
+	for i, name := range instanceNames {
+		instances[formatInstance(params.InstanceStart+i, params.InstancePadding)] = name
+	}
+	return instances
+}
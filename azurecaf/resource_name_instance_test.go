@@ -0,0 +1,128 @@
+package azurecaf
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGenerateInstanceNames(t *testing.T) {
+	params := nameParameters{
+		Name:            "app",
+		Separator:       "-",
+		ResourceType:    "azurerm_linux_virtual_machine",
+		CleanInput:      true,
+		UseSlug:         true,
+		InstanceStart:   9,
+		InstanceCount:   3,
+		InstancePadding: 3,
+	}
+	names, err := generateInstanceNames(params)
+	if err != nil {
+		t.Fatalf("generateInstanceNames failed: %v", err)
+	}
+	if expected := []string{"vm-app-009", "vm-app-010", "vm-app-011"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+	if instances := nameInstances(params, names); instances["010"] != "vm-app-010" || len(instances) != 3 {
+		t.Errorf("Expected the names by instance number, got %v", instances)
+	}
+
+	params.ResourceType = ""
+	if _, err := generateInstanceNames(params); err == nil {
+		t.Error("Expected instance_count to require resource_type")
+	}
+}
+
+func TestGenerateInstanceNames_Protected(t *testing.T) {
+	// Windows virtual machines are limited to 15 characters, vm-payments-prd fits
+	// without instance number, the suffix is dropped for it
+	params := nameParameters{
+		Name:            "payments",
+		Suffixes:        []string{"prd"},
+		Separator:       "-",
+		ResourceType:    "azurerm_windows_virtual_machine",
+		CleanInput:      true,
+		UseSlug:         true,
+		InstanceStart:   1,
+		InstanceCount:   2,
+		InstancePadding: 2,
+	}
+	names, err := generateInstanceNames(params)
+	if err != nil {
+		t.Fatalf("generateInstanceNames failed: %v", err)
+	}
+	if expected := []string{"vm-payments-01", "vm-payments-02"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	params.InstancePadding = 16
+	if _, err := generateInstanceNames(params); err == nil || !strings.Contains(err.Error(), "does not fit") {
+		t.Errorf("Expected an instance longer than the name to fail, got %v", err)
+	}
+}
+
+func TestResourceNameInstances(t *testing.T) {
+	rd := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":           "app",
+		"resource_type":  "azurerm_storage_account",
+		"random_length":  4,
+		"instance_count": 2,
+	})
+	if diags := resourceNameCreate(context.Background(), rd, nil); diags.HasError() {
+		t.Fatalf("resourceNameCreate failed: %v", diags)
+	}
+	random := rd.Get("random_string").(string)
+	expected := []interface{}{"stapp" + random + "001", "stapp" + random + "002"}
+	if list := rd.Get("results_list").([]interface{}); !reflect.DeepEqual(list, expected) {
+		t.Errorf("Expected %v, got %v", expected, list)
+	}
+	if instances := rd.Get("instances").(map[string]interface{}); instances["002"] != expected[1] {
+		t.Errorf("Expected the names by instance number, got %v", instances)
+	}
+
+	data := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{
+		"name":             "app",
+		"resource_type":    "azurerm_linux_virtual_machine",
+		"instance_count":   2,
+		"instance_start":   0,
+		"instance_padding": 2,
+	})
+	if diags := dataNameRead(context.Background(), data, nil); diags.HasError() {
+		t.Fatalf("dataNameRead failed: %v", diags)
+	}
+	if list := data.Get("results_list").([]interface{}); !reflect.DeepEqual(list, []interface{}{"vm-app-00", "vm-app-01"}) {
+		t.Errorf("Expected vm-app-00 and vm-app-01, got %v", list)
+	}
+}
+
+func TestResourceNameInstances_Duplicates(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"on_duplicate_name": DuplicateNameError})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("providerConfigure failed: %v", diags)
+	}
+
+	instances := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":             "app",
+		"resource_type":    "azurerm_linux_virtual_machine",
+		"instance_count":   2,
+		"instance_start":   0,
+		"instance_padding": 2,
+	})
+	if _, err := getNameResult(instances, meta); err != nil {
+		t.Fatalf("getNameResult failed: %v", err)
+	}
+
+	// vm-app-01 is the name of the second instance
+	single := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":          "app-01",
+		"resource_type": "azurerm_linux_virtual_machine",
+	})
+	if _, err := getNameResult(single, meta); err == nil || !strings.Contains(err.Error(), "name vm-app-01 of azurerm_linux_virtual_machine is generated more than once") {
+		t.Errorf("Expected the name of an instance to be a duplicate, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package azurecaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// nameStateType is the type of the state of azurecaf_name on the protocol.
+func nameStateType() cty.Type {
+	return resourceName().CoreConfigSchema().ImpliedType()
+}
+
+func packNameValue(t *testing.T, value cty.Value) *tfprotov5.DynamicValue {
+	t.Helper()
+	packed, err := msgpack.Marshal(value, nameStateType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tfprotov5.DynamicValue{MsgPack: packed}
+}
+
+func unpackNameValue(t *testing.T, value *tfprotov5.DynamicValue) cty.Value {
+	t.Helper()
+	if value == nil {
+		t.Fatal("Expected a value, got none")
+	}
+	unpacked, err := msgpack.Unmarshal(value.MsgPack, nameStateType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return unpacked
+}
+
+func checkProtocolDiagnostics(t *testing.T, operation string, diagnostics []*tfprotov5.Diagnostic) {
+	t.Helper()
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("%s failed: %s: %s", operation, diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+}
+
+// upgradeNameState upgrades the JSON state of an azurecaf_name written with the given
+// schema version, the way Terraform does before the first refresh.
+func upgradeNameState(t *testing.T, server tfprotov5.ProviderServer, version int64, state string) cty.Value {
+	t.Helper()
+	resp, err := server.UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: "azurecaf_name",
+		Version:  version,
+		RawState: &tfprotov5.RawState{JSON: []byte(state)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkProtocolDiagnostics(t, "UpgradeResourceState", resp.Diagnostics)
+	return unpackNameValue(t, resp.UpgradedState)
+}
+
+// importNameState imports an azurecaf_name with the given import ID.
+func importNameState(t *testing.T, server tfprotov5.ProviderServer, importID string) cty.Value {
+	t.Helper()
+	resp, err := server.ImportResourceState(context.Background(), &tfprotov5.ImportResourceStateRequest{
+		TypeName: "azurecaf_name",
+		ID:       importID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkProtocolDiagnostics(t, "ImportResourceState", resp.Diagnostics)
+	if len(resp.ImportedResources) != 1 {
+		t.Fatalf("Expected one imported resource, got %d", len(resp.ImportedResources))
+	}
+	return unpackNameValue(t, resp.ImportedResources[0].State)
+}
+
+// planNameChanges refreshes state and plans config against it, the way terraform plan
+// does, and returns the planned state and the attributes replacing the resource.
+func planNameChanges(t *testing.T, server tfprotov5.ProviderServer, state cty.Value, config map[string]cty.Value) (cty.Value, cty.Value, []*tftypes.AttributePath) {
+	t.Helper()
+	read, err := server.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+		TypeName:     "azurecaf_name",
+		CurrentState: packNameValue(t, state),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkProtocolDiagnostics(t, "ReadResource", read.Diagnostics)
+	prior := unpackNameValue(t, read.NewState)
+
+	// The proposed new state keeps the computed values of the prior state, like
+	// Terraform does, the configured values replace the others
+	attributes := resourceName().CoreConfigSchema().Attributes
+	configValues := map[string]cty.Value{}
+	proposedValues := map[string]cty.Value{}
+	for key, attributeType := range nameStateType().AttributeTypes() {
+		configValue, found := config[key]
+		if !found {
+			configValue = cty.NullVal(attributeType)
+		}
+		configValues[key] = configValue
+		proposedValues[key] = configValue
+		if attribute := attributes[key]; attribute.Computed && (!attribute.Optional || configValue.IsNull()) {
+			proposedValues[key] = prior.GetAttr(key)
+		}
+	}
+
+	plan, err := server.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "azurecaf_name",
+		PriorState:       packNameValue(t, prior),
+		ProposedNewState: packNameValue(t, cty.ObjectVal(proposedValues)),
+		Config:           packNameValue(t, cty.ObjectVal(configValues)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkProtocolDiagnostics(t, "PlanResourceChange", plan.Diagnostics)
+	return prior, unpackNameValue(t, plan.PlannedState), plan.RequiresReplace
+}
+
+// checkEmptyNamePlan fails when planning config against state changes an attribute.
+func checkEmptyNamePlan(t *testing.T, server tfprotov5.ProviderServer, state cty.Value, config map[string]cty.Value) {
+	t.Helper()
+	prior, planned, requiresReplace := planNameChanges(t, server, state, config)
+	if len(requiresReplace) > 0 {
+		t.Errorf("Expected no replacement, got %v", requiresReplace)
+	}
+	for key := range nameStateType().AttributeTypes() {
+		if before, after := prior.GetAttr(key), planned.GetAttr(key); !before.RawEquals(after) {
+			t.Errorf("Expected %s not to change, planned %#v to %#v", key, before, after)
+		}
+	}
+}
+
+func TestResourceName_PlanMissingAttributes(t *testing.T) {
+	server := GRPCProviderServer()
+	// A version 4 state written before the computed attributes and the defaults of
+	// separator_fallback, uniqueness and abbreviate were set on every path
+	state := upgradeNameState(t, server, 4, `{
+		"id": "abcdefghijklmnop",
+		"name": "app",
+		"prefixes": ["dev"],
+		"suffixes": [],
+		"random_length": 0,
+		"result": "rg-dev-app",
+		"results": {"azurerm_key_vault": "kv-dev-app"},
+		"separator": "-",
+		"clean_input": true,
+		"passthrough": false,
+		"resource_type": "azurerm_resource_group",
+		"resource_types": ["azurerm_key_vault"],
+		"use_slug": true,
+		"instance_count": 0,
+		"instance_start": 1,
+		"instance_padding": 3,
+		"random_string": "",
+		"created_at": "2026-01-01T00:00:00Z"
+	}`)
+	config := map[string]cty.Value{
+		"name":           cty.StringVal("app"),
+		"prefixes":       cty.ListVal([]cty.Value{cty.StringVal("dev")}),
+		"resource_type":  cty.StringVal("azurerm_resource_group"),
+		"resource_types": cty.ListVal([]cty.Value{cty.StringVal("azurerm_key_vault")}),
+	}
+	checkEmptyNamePlan(t, server, state, config)
+
+	// The refresh derives the missing attributes from the arguments
+	refreshed, _, _ := planNameChanges(t, server, state, config)
+	if scopes := refreshed.GetAttr("scopes"); scopes.IsNull() || scopes.Index(cty.StringVal("azurerm_key_vault")).AsString() != ScopeGlobal {
+		t.Errorf("Expected the scopes to be derived from the arguments, got %#v", scopes)
+	}
+	if separators := refreshed.GetAttr("separators"); separators.IsNull() || separators.Index(cty.StringVal("azurerm_resource_group")).AsString() != "-" {
+		t.Errorf("Expected the separators to be derived from the arguments, got %#v", separators)
+	}
+}
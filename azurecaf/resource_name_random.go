@@ -0,0 +1,67 @@
+package azurecaf
+
+// fillsRandom reports whether the convention fills the names with random characters
+// up to the maximum length of each resource type.
+func (params nameParameters) fillsRandom() bool {
+	return params.BuiltinConvention == ConventionCafRandom || params.BuiltinConvention == ConventionRandom
+}
+
+// randomPartLength returns the length of the random part drawn for params. The
+// cafrandom and random conventions draw a single random part long enough for the
+// longest resource type, and each name uses the characters it has room for.
+func (params nameParameters) randomPartLength() int {
+	length := params.RandomLength
+	if !params.fillsRandom() {
+		return length
+	}
+	for _, resourceTypeName := range append([]string{params.ResourceType}, params.ResourceTypes...) {
+		if resource, err := getResource(resourceTypeName); err == nil && resource.MaxLength > length {
+			length = resource.MaxLength
+		}
+	}
+	return length
+}
+
+// composeRandom composes a cafrandom or random name for resourceTypeName. The random
+// convention only keeps the random part. The cafrandom convention keeps the components
+// of the name and gives the room left by them to the random part, which is never
+// shorter than random_length.
+func composeRandom(resourceTypeName string, params nameParameters, inputs nameInputs, compose func(string, nameInputs) (string, error)) (string, error) {
+	resource, err := getResource(resourceTypeName)
+	if err != nil {
+		return "", err
+	}
+	random := inputs.Random
+	if params.BuiltinConvention == ConventionRandom {
+		// The uniqueness hash and the instance number, if any, keep their room after
+		// the random characters
+		room := resource.MaxLength
+		if inputs.Instance != "" {
+			room -= len(inputs.Separator) + len(inputs.Instance)
+		}
+		if uniquenessHash(resource, params.Uniqueness, params.UniquenessSeed, nameInputs{}) != "" {
+			room -= len(inputs.Separator) + uniquenessHashLength
+		}
+		return compose(resourceTypeName, nameInputs{Separator: inputs.Separator, Random: random[:max(0, min(len(random), room))], Instance: inputs.Instance})
+	}
+
+	// Compose the name with one and two random characters: when both fit, the second
+	// one only adds a character and the room left is known, separators included.
+	one, two := inputs, inputs
+	one.Random, two.Random = random[:min(len(random), 1)], random[:min(len(random), 2)]
+	oneName, err := compose(resourceTypeName, one)
+	if err != nil {
+		return "", err
+	}
+	twoName, err := compose(resourceTypeName, two)
+	if err != nil {
+		return "", err
+	}
+
+	length := params.RandomLength
+	if len(twoName) == len(oneName)+1 {
+		length = max(length, resource.MaxLength-len(oneName)+1)
+	}
+	inputs.Random = random[:min(len(random), length)]
+	return compose(resourceTypeName, inputs)
+}
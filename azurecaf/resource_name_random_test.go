@@ -0,0 +1,132 @@
+package azurecaf
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRandSeq_Seeded(t *testing.T) {
+	seed := int64(42)
+	first := randSeq(24, &seed)
+	if second := randSeq(24, &seed); second != first {
+		t.Errorf("Expected the same random part for the same seed, got %s and %s", first, second)
+	}
+	if short := randSeq(5, &seed); short != first[:5] {
+		t.Errorf("Expected %s to start the longer random part %s", short, first)
+	}
+}
+
+func TestBuiltinConventions_Names(t *testing.T) {
+	rd := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":           "myapp",
+		"prefixes":       []interface{}{"dev"},
+		"resource_type":  "azurerm_storage_account",
+		"resource_types": []interface{}{"azurerm_key_vault", "azurerm_resource_group"},
+		"convention":     ConventionCafRandom,
+		"random_seed":    42,
+	})
+	if _, err := getNameResult(rd, nil); err != nil {
+		t.Fatalf("getNameResult failed: %v", err)
+	}
+
+	result := rd.Get("result").(string)
+	if len(result) != 24 || !strings.HasPrefix(result, "devstmyapp") {
+		t.Errorf("Expected a storage account name filled to 24 characters, got %s", result)
+	}
+	randomString := rd.Get("random_string").(string)
+	if len(randomString) != ResourceDefinitions["azurerm_resource_group"].MaxLength {
+		t.Errorf("Expected a random part for the longest resource type, got %d characters", len(randomString))
+	}
+	results := rd.Get("results").(map[string]interface{})
+	if keyVault := results["azurerm_key_vault"].(string); keyVault != "dev-kv-myapp-"+randomString[:11] {
+		t.Errorf("Unexpected key vault name %s", keyVault)
+	}
+	if resourceGroup := results["azurerm_resource_group"].(string); len(resourceGroup) != 90 {
+		t.Errorf("Expected a resource group name filled to 90 characters, got %s", resourceGroup)
+	}
+
+	// The same seed generates the same names
+	again := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":          "myapp",
+		"prefixes":      []interface{}{"dev"},
+		"resource_type": "azurerm_storage_account",
+		"convention":    ConventionCafRandom,
+		"random_seed":   42,
+	})
+	if _, err := getNameResult(again, nil); err != nil {
+		t.Fatalf("getNameResult failed: %v", err)
+	}
+	if again.Get("result").(string) != result {
+		t.Errorf("Expected %s for the same seed, got %s", result, again.Get("result").(string))
+	}
+
+	random := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":          "myapp",
+		"resource_type": "azurerm_key_vault",
+		"convention":    ConventionRandom,
+		"random_seed":   42,
+	})
+	if _, err := getNameResult(random, nil); err != nil {
+		t.Fatalf("getNameResult failed: %v", err)
+	}
+	if name := random.Get("result").(string); name != randomString[:24] {
+		t.Errorf("Expected a key vault name made of the random part only, got %s", name)
+	}
+}
+
+func TestBuiltinConventions_AllResourceTypes(t *testing.T) {
+	for _, convention := range []string{ConventionCafRandom, ConventionRandom} {
+		for resourceType, resource := range ResourceDefinitions {
+			result, _, err := generateNames(nameParameters{
+				Name:              "myapp",
+				Separator:         "-",
+				ResourceType:      resourceType,
+				CleanInput:        true,
+				UseSlug:           true,
+				RandomSeed:        42,
+				BuiltinConvention: convention,
+			})
+			if err != nil {
+				t.Errorf("%s %s: %v", convention, resourceType, err)
+				continue
+			}
+			if !regexp.MustCompile(resource.ValidationRegExp).MatchString(result) {
+				t.Errorf("%s %s: %s does not match %s", convention, resourceType, result, resource.ValidationRegExp)
+			}
+			if convention == ConventionRandom && len(result) != resource.MaxLength {
+				t.Errorf("%s %s: expected %d characters, got %s", convention, resourceType, resource.MaxLength, result)
+			}
+		}
+	}
+}
+
+func TestDataName_Convention(t *testing.T) {
+	config := map[string]interface{}{
+		"name":          "myapp",
+		"resource_type": "azurerm_storage_account",
+		"convention":    ConventionCafRandom,
+		"random_seed":   42,
+	}
+	first := schema.TestResourceDataRaw(t, dataName().Schema, config)
+	if diags := dataNameRead(context.Background(), first, nil); diags.HasError() {
+		t.Fatalf("dataNameRead failed: %v", diags)
+	}
+	second := schema.TestResourceDataRaw(t, dataName().Schema, config)
+	if diags := dataNameRead(context.Background(), second, nil); diags.HasError() {
+		t.Fatalf("dataNameRead failed: %v", diags)
+	}
+	result := first.Get("result").(string)
+	if len(result) != 24 || result != second.Get("result").(string) {
+		t.Errorf("Expected the same 24 characters name for the same seed, got %s and %s", result, second.Get("result").(string))
+	}
+
+	config["convention"] = "undefined"
+	undefined := schema.TestResourceDataRaw(t, dataName().Schema, config)
+	if diags := dataNameRead(context.Background(), undefined, nil); !diags.HasError() {
+		t.Error("Expected an error for a convention missing from the provider configuration")
+	}
+}
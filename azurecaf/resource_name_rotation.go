@@ -0,0 +1,63 @@
+package azurecaf
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// clock returns the current time. It is replaced in tests to check rotations offline.
+var clock = time.Now
+
+// checkRotation rejects rotation_days and rotate_at when a rotation would generate the
+// same names again, replacing the resource for nothing: without a random part, or with
+// a random part drawn from random_seed.
+func checkRotation(d *schema.ResourceDiff) error {
+	if d.Get("rotation_days").(int) == 0 && d.Get("rotate_at").(string) == "" {
+		return nil
+	}
+	if !d.NewValueKnown("random_seed") || !d.NewValueKnown("random_length") || !d.NewValueKnown("convention") {
+		return nil
+	}
+	if d.Get("random_seed").(int) != 0 {
+		return fmt.Errorf("rotation_days and rotate_at cannot be used with random_seed, a rotation would generate the same random part")
+	}
+	convention := d.Get("convention").(string)
+	if d.Get("random_length").(int) == 0 && convention != ConventionCafRandom && convention != ConventionRandom {
+		return fmt.Errorf("rotation_days and rotate_at require a random part, set random_length or the cafrandom or random convention")
+	}
+	return nil
+}
+
+// rotationDue reports whether the names must be generated again because the rotation
+// window of rotation_days or the rotate_at timestamp passed since created_at.
+func rotationDue(d *schema.ResourceDiff) (bool, error) {
+	now := clock().UTC()
+
+	var createdAt time.Time
+	if value := d.Get("created_at").(string); value != "" {
+		var err error
+		if createdAt, err = time.Parse(time.RFC3339, value); err != nil {
+			return false, fmt.Errorf("invalid created_at timestamp %s: %w", value, err)
+		}
+	}
+
+	if days := d.Get("rotation_days").(int); days > 0 && !createdAt.IsZero() {
+		if !now.Before(createdAt.AddDate(0, 0, days)) {
+			return true, nil
+		}
+	}
+
+	if value := d.Get("rotate_at").(string); value != "" && d.NewValueKnown("rotate_at") {
+		rotateAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return false, fmt.Errorf("invalid rotate_at timestamp %s: %w", value, err)
+		}
+		// rotate_at triggers a single rotation, names created after it are kept
+		if !now.Before(rotateAt) && createdAt.Before(rotateAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
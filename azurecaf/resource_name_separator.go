@@ -0,0 +1,111 @@
+package azurecaf
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Separator fallbacks of azurecaf_name.
+const (
+	// SeparatorFallbackNone removes the separator characters a resource type does not allow.
+	SeparatorFallbackNone = "none"
+	// SeparatorFallbackAuto replaces a separator a resource type does not allow.
+	SeparatorFallbackAuto = "auto"
+	// SeparatorCamelCase is reported when the components are joined in camel case.
+	SeparatorCamelCase = "camelCase"
+)
+
+// fallbackSeparator returns the separator used between the components of a name of
+// resource. A separator is not allowed when it has dashes and the resource does not
+// allow them, when the cleaning regex removes it or when the validation pattern rejects
+// it. It is then cleaned, unless the fallback is auto: it is replaced by the first of _
+// and . that is allowed, by a camel case join when uppercase letters are allowed, or
+// removed.
+func fallbackSeparator(resource *ResourceStructure, separator string, fallback string) string {
+	cleanRegEx, validationRegEx, err := resourceRegexps(resource)
+	if err != nil {
+		// getResource rejects the definitions whose regular expressions do not compile
+		return ""
+	}
+	if separatorAllowed(resource, cleanRegEx, validationRegEx, separator) {
+		return separator
+	}
+	if fallback != SeparatorFallbackAuto {
+		return cleanRegEx.ReplaceAllString(separator, "")
+	}
+	for _, candidate := range []string{"_", "."} {
+		if separatorAllowed(resource, cleanRegEx, validationRegEx, candidate) {
+			return candidate
+		}
+	}
+	if !resource.LowerCase && cleanRegEx.ReplaceAllString("A", "") == "A" && validationAccepts(resource, validationRegEx, "A") {
+		return SeparatorCamelCase
+	}
+	return ""
+}
+
+func separatorAllowed(resource *ResourceStructure, cleanRegEx *regexp.Regexp, validationRegEx *regexp.Regexp, separator string) bool {
+	if separator == "" {
+		return true
+	}
+	if strings.Contains(separator, "-") && !resource.Dashes {
+		return false
+	}
+	return cleanRegEx.ReplaceAllString(separator, "") == separator && validationAccepts(resource, validationRegEx, separator)
+}
+
+// validationAccepts reports whether the validation pattern of resource accepts joint
+// between two components.
+func validationAccepts(resource *ResourceStructure, validationRegEx *regexp.Regexp, joint string) bool {
+	side := max(1, (resource.MinLength-len(joint)+1)/2)
+	probe := strings.Repeat("a", side) + joint + strings.Repeat("a", side)
+	return len(probe) > resource.MaxLength || validationRegEx.MatchString(probe)
+}
+
+// nameSeparators returns the separator used for each resource type of params, once the
+// named convention, the naming policy and the separator fallback are applied.
+func nameSeparators(params nameParameters) map[string]string {
+	separator := params.Separator
+	if params.Convention != nil && params.Convention.Separator != "" {
+		separator = params.Convention.Separator
+	}
+	if params.Policy != nil && params.Policy.Separator != "" {
+		separator = params.Policy.Separator
+	}
+
+	separators := make(map[string]string, len(params.ResourceTypes)+1)
+	for _, resourceTypeName := range append([]string{params.ResourceType}, params.ResourceTypes...) {
+		if resourceTypeName == "" {
+			continue
+		}
+		if resource, err := getResource(resourceTypeName); err == nil {
+			separators[resourceTypeName] = fallbackSeparator(resource, separator, params.SeparatorFallback)
+		}
+	}
+	return separators
+}
+
+func capitalize(value string) string {
+	first, size := utf8.DecodeRuneInString(value)
+	if size == 0 {
+		return value
+	}
+	return strings.ToUpper(string(first)) + value[size:]
+}
+
+func uncapitalize(value string) string {
+	first, size := utf8.DecodeRuneInString(value)
+	if size == 0 {
+		return value
+	}
+	return strings.ToLower(string(first)) + value[size:]
+}
+
+func capitalizeAll(values []string) []string {
+	capitalized := make([]string, len(values))
+	for i, value := range values {
+		capitalized[i] = capitalize(value)
+	}
+	return capitalized
+}
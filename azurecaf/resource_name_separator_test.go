@@ -0,0 +1,108 @@
+package azurecaf
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSeparatorFallback_Names(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		fallback     string
+		expected     string
+	}{
+		{"azurerm_key_vault", SeparatorFallbackAuto, "prd-kv-app-01"},
+		{"azurerm_shared_image_gallery", SeparatorFallbackNone, "prdsigapp01"},
+		{"azurerm_shared_image_gallery", "", "prdsigapp01"},
+		{"azurerm_shared_image_gallery", SeparatorFallbackAuto, "prd_sig_app_01"},
+		{"azurerm_cdn_frontdoor_firewall_policy", SeparatorFallbackNone, "prdcfdfpapp01"},
+		{"azurerm_cdn_frontdoor_firewall_policy", SeparatorFallbackAuto, "prdCfdfpApp01"},
+		// Uppercase letters are allowed by the cleaning regex but not by the validation pattern
+		{"azurerm_kusto_cluster", SeparatorFallbackAuto, "prdkcapp01"},
+		{"azurerm_storage_account", SeparatorFallbackAuto, "prdstapp01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resourceType+"/"+tt.fallback, func(t *testing.T) {
+			result, _, err := generateNames(nameParameters{
+				Name:              "app",
+				Prefixes:          []string{"prd"},
+				Suffixes:          []string{"01"},
+				Separator:         "-",
+				ResourceType:      tt.resourceType,
+				CleanInput:        true,
+				UseSlug:           true,
+				SeparatorFallback: tt.fallback,
+			})
+			if err != nil {
+				t.Fatalf("generateNames failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSeparatorFallback_AllResourceTypes(t *testing.T) {
+	for resourceType, resource := range ResourceDefinitions {
+		result, _, err := generateNames(nameParameters{
+			Name:              "app",
+			Prefixes:          []string{"prd"},
+			Suffixes:          []string{"01"},
+			Separator:         "-",
+			ResourceType:      resourceType,
+			CleanInput:        true,
+			UseSlug:           true,
+			SeparatorFallback: SeparatorFallbackAuto,
+		})
+		if err != nil {
+			t.Errorf("%s: %v", resourceType, err)
+			continue
+		}
+		if !regexp.MustCompile(resource.ValidationRegExp).MatchString(result) {
+			t.Errorf("%s: %s does not match %s", resourceType, result, resource.ValidationRegExp)
+		}
+	}
+}
+
+func TestSeparatorFallback_Separators(t *testing.T) {
+	rd := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":               "app",
+		"resource_type":      "azurerm_key_vault",
+		"resource_types":     []interface{}{"azurerm_storage_account", "azurerm_shared_image_gallery", "azurerm_cdn_frontdoor_firewall_policy"},
+		"separator_fallback": SeparatorFallbackAuto,
+	})
+	if _, err := getNameResult(rd, nil); err != nil {
+		t.Fatalf("getNameResult failed: %v", err)
+	}
+	expected := map[string]string{
+		"azurerm_key_vault":                     "-",
+		"azurerm_storage_account":               "",
+		"azurerm_shared_image_gallery":          "_",
+		"azurerm_cdn_frontdoor_firewall_policy": SeparatorCamelCase,
+	}
+	separators := rd.Get("separators").(map[string]interface{})
+	for resourceType, separator := range expected {
+		if separators[resourceType] != separator {
+			t.Errorf("Expected separator %q for %s, got %v", separator, resourceType, separators[resourceType])
+		}
+	}
+
+	data := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{
+		"name":               "app",
+		"resource_type":      "azurerm_shared_image_gallery",
+		"separator_fallback": SeparatorFallbackAuto,
+	})
+	if diags := dataNameRead(context.Background(), data, nil); diags.HasError() {
+		t.Fatalf("dataNameRead failed: %v", diags)
+	}
+	if result := data.Get("result").(string); result != "sig_app" {
+		t.Errorf("Expected sig_app, got %s", result)
+	}
+	if separator := data.Get("separators").(map[string]interface{})["azurerm_shared_image_gallery"]; separator != "_" {
+		t.Errorf("Expected the data source to report _, got %v", separator)
+	}
+}
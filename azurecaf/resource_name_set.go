@@ -0,0 +1,372 @@
+package azurecaf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceNameSet creates and returns the schema for the azurecaf_name_set resource.
+//
+// A name set generates the names of the resources of a workload at once. Every name
+// shares the same components and random part, and each resource type can override
+// the name, the suffixes and the slug. Unlike the results of azurecaf_name, the random
+// part is never dropped to fit a resource type, so that it identifies every member.
+func resourceNameSet() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceNameSetCreate,
+		Read:          schema.Noop,
+		Delete:        resourceNameDelete,
+		Schema:        nameSetSchema(),
+	}
+}
+
+// nameSetSchema returns the schema shared by the azurecaf_name_set resource and data source.
+func nameSetSchema() map[string]*schema.Schema {
+	resourceMapsKeys := make([]string, 0, len(ResourceDefinitions))
+	for k := range ResourceDefinitions {
+		resourceMapsKeys = append(resourceMapsKeys, k)
+	}
+
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+			Default:  "",
+		},
+		"prefixes": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			Optional: true,
+			ForceNew: true,
+		},
+		"suffixes": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.NoZeroValues,
+			},
+			Optional: true,
+			ForceNew: true,
+		},
+		"separator": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+			Default:  "-",
+		},
+		"clean_input": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+			Default:  true,
+		},
+		"use_slug": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+			Default:  true,
+		},
+		// Number of random characters shared by every name
+		"random_length": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Default:      0,
+		},
+		"random_seed": {
+			Type:     schema.TypeInt,
+			Optional: true,
+			ForceNew: true,
+		},
+		// Named convention of the provider, the built-in ones drawing their own random
+		// part are not supported
+		"convention": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+		// Replacement of a separator the resource type does not allow
+		"separator_fallback": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice([]string{SeparatorFallbackNone, SeparatorFallbackAuto}, false),
+		},
+		// Replace the words of the components with their abbreviation
+		"abbreviate": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+			Default:  false,
+		},
+		// Words replaced by an abbreviation
+		"abbreviations": {
+			Type: schema.TypeMap,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+		// Hash added to the names of the resource types with a global scope
+		"uniqueness": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringInSlice([]string{UniquenessNone, UniquenessAuto}, false),
+		},
+		// Added to the input of the uniqueness hash, e.g. a subscription ID
+		"uniqueness_seed": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+		// Key of the scope the names are unique in, for the name registry and ledger
+		"scope_key": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+		// Region whose short code is added before the suffixes
+		"location": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validateLocation,
+		},
+		"resource_types": {
+			Type: schema.TypeList,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(resourceMapsKeys, false),
+			},
+			Required: true,
+			ForceNew: true,
+			MinItems: 1,
+		},
+		// Components of the name of one resource type of resource_types
+		"override": {
+			Type:     schema.TypeList,
+			Optional: true,
+			ForceNew: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"resource_type": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ForceNew:     true,
+						ValidateFunc: validation.StringInSlice(resourceMapsKeys, false),
+					},
+					"name": {
+						Type:     schema.TypeString,
+						Optional: true,
+						ForceNew: true,
+					},
+					"suffixes": {
+						Type: schema.TypeList,
+						Elem: &schema.Schema{
+							Type:         schema.TypeString,
+							ValidateFunc: validation.NoZeroValues,
+						},
+						Optional: true,
+						ForceNew: true,
+					},
+					"slug": {
+						Type:     schema.TypeString,
+						Optional: true,
+						ForceNew: true,
+					},
+				},
+			},
+		},
+		"results": {
+			Type: schema.TypeMap,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Computed: true,
+		},
+		"random_string": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+// nameSetOverride replaces the components of the name of one resource type of a name set.
+type nameSetOverride struct {
+	Name     string
+	Suffixes []string
+	Slug     string
+}
+
+func resourceNameSetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id := randSeq(16, nil)
+	diags, err := getNameSetResult(d, meta, id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(id)
+	return diags
+}
+
+// nameSetArguments reads the arguments of an azurecaf_name_set resource or data source
+// for readNameParameters. The arguments of azurecaf_name a name set does not have read
+// as their default in nameSchema.
+type nameSetArguments struct {
+	d          *schema.ResourceData
+	nameSchema map[string]*schema.Schema
+}
+
+func (arguments nameSetArguments) Get(key string) interface{} {
+	switch key {
+	case "resource_type", "passthrough", "instance_count", "instance_start", "instance_padding":
+		return rawNameState{schema: arguments.nameSchema}.Get(key)
+	}
+	return arguments.d.Get(key)
+}
+
+// readNameSetParameters builds the naming pipeline inputs and the overrides from the
+// arguments of an azurecaf_name_set resource or data source, like readNameParameters
+// does for azurecaf_name. The built-in conventions drawing a random part for each
+// resource type are rejected: every name of a set shares one random part.
+func readNameSetParameters(d *schema.ResourceData, meta interface{}) (nameParameters, map[string]nameSetOverride, error) {
+	params, err := readNameParameters(nameSetArguments{d: d, nameSchema: dataName().Schema}, meta)
+	if err != nil {
+		return params, nil, err
+	}
+	if params.fillsRandom() || params.Passthrough {
+		return params, nil, fmt.Errorf("convention %s is not supported by azurecaf_name_set, whose names share one random part", params.BuiltinConvention)
+	}
+	overrides := map[string]nameSetOverride{}
+	for _, raw := range d.Get("override").([]interface{}) {
+		block := raw.(map[string]interface{})
+		resourceTypeName := block["resource_type"].(string)
+		if _, found := overrides[resourceTypeName]; found {
+			return params, nil, fmt.Errorf("resource type %s is overridden more than once", resourceTypeName)
+		}
+		overrides[resourceTypeName] = nameSetOverride{
+			Name:     block["name"].(string),
+			Suffixes: convertInterfaceToString(block["suffixes"].([]interface{})),
+			Slug:     block["slug"].(string),
+		}
+	}
+	return params, overrides, nil
+}
+
+// getNameSetResult composes the names of a name set and sets them in d. The names of
+// the resource, owned by owner, are checked for availability and reserved in the name
+// ledger like those of azurecaf_name. The data source, without owner, only registers
+// its names. The returned warnings report the names generated more than once during
+// the run.
+func getNameSetResult(d *schema.ResourceData, meta interface{}, owner string) (diag.Diagnostics, error) {
+	params, overrides, err := readNameSetParameters(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	params.RandomString = randSeq(params.RandomLength, &params.RandomSeed)
+	params.Replacements = map[string]string{}
+	scopeKey := d.Get("scope_key").(string)
+	var results map[string]string
+	generate := func() (map[string][]string, error) {
+		var err error
+		if results, err = generateNameSet(params, overrides); err != nil {
+			return nil, err
+		}
+		return nameResults(nameParameters{}, "", results, nil), nil
+	}
+	if owner == "" {
+		_, err = generate()
+	} else {
+		err = reserveNames(meta, owner, scopeKey, &params, map[string]bool{}, generate)
+	}
+	if err != nil {
+		return nil, err
+	}
+	diags, err := registerNames(meta, owner, scopeKey, nameResults(nameParameters{}, "", results, nil))
+	if err != nil {
+		if ledger := ledgerFromMeta(meta); ledger != nil && owner != "" {
+			ledger.release(owner)
+		}
+		return nil, err
+	}
+	d.Set("results", results)
+	d.Set("random_string", params.RandomString)
+	d.Set("abbreviations", params.Replacements)
+	return diags, nil
+}
+
+// generateNameSet returns the name of each resource type of params, with the components
+// of its override, if any. The random part joins the name component, which is composed
+// first, so that no resource type drops it: the name is shortened to keep it instead.
+func generateNameSet(params nameParameters, overrides map[string]nameSetOverride) (map[string]string, error) {
+	if _, err := validateResourceType("", params.ResourceTypes); err != nil {
+		return nil, err
+	}
+	for resourceTypeName := range overrides {
+		if !containsString(params.ResourceTypes, resourceTypeName) {
+			return nil, fmt.Errorf("resource type %s is overridden but not in resource_types", resourceTypeName)
+		}
+	}
+
+	random := params.RandomString
+	results := make(map[string]string, len(params.ResourceTypes))
+	for _, resourceTypeName := range params.ResourceTypes {
+		resource, err := getResource(resourceTypeName)
+		if err != nil {
+			return nil, err
+		}
+		member := params
+		member.ResourceType, member.ResourceTypes = resourceTypeName, nil
+		member.RandomLength, member.RandomString = 0, ""
+		if override, found := overrides[resourceTypeName]; found {
+			if override.Name != "" {
+				member.Name = override.Name
+			}
+			if len(override.Suffixes) > 0 {
+				member.Suffixes = override.Suffixes
+			}
+			member.Slug = override.Slug
+		}
+		if random != "" {
+			separator := nameSeparators(member)[resourceTypeName]
+			if separator == SeparatorCamelCase {
+				separator = ""
+			}
+			room := resource.MaxLength - len(separator) - len(random)
+			// The uniqueness hash ends the name and keeps its room too
+			if uniquenessHash(resource, member.Uniqueness, member.UniquenessSeed, nameInputs{}) != "" {
+				room -= len(separator) + uniquenessHashLength
+			}
+			room = max(0, room)
+			// Abbreviate the name before it is shortened to keep the random part
+			name := member.Name
+			if member.Abbreviate {
+				name = abbreviateWords(name, member.Environments, member.Replacements)
+				if len(name) > room {
+					name = abbreviateWords(name, member.Abbreviations, member.Replacements)
+				}
+			}
+			member.Name = concatenateParameters(separator, []string{name[:min(len(name), room)], random})
+		}
+
+		name, err := nameComposer(member)(resourceTypeName)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.Contains(strings.ToLower(name), random) {
+			return nil, fmt.Errorf("the random part %s does not fit in the name %s of %s", random, name, resourceTypeName)
+		}
+		results[resourceTypeName] = name
+	}
+	return results, nil
+}
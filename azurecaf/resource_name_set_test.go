@@ -0,0 +1,200 @@
+package azurecaf
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGenerateNameSet(t *testing.T) {
+	params := nameParameters{
+		Name:     "payments",
+		Prefixes: []string{"contoso"},
+		Suffixes: []string{"prd"},
+		ResourceTypes: []string{
+			"azurerm_resource_group",
+			"azurerm_key_vault",
+			"azurerm_storage_account",
+			"azurerm_log_analytics_workspace",
+			"azurerm_app_service_plan",
+			"azurerm_linux_web_app",
+		},
+		Separator:    "-",
+		CleanInput:   true,
+		UseSlug:      true,
+		RandomString: "xkcdq",
+	}
+	results, err := generateNameSet(params, map[string]nameSetOverride{
+		"azurerm_linux_web_app":    {Name: "api", Slug: "web"},
+		"azurerm_app_service_plan": {Suffixes: []string{"linux"}},
+	})
+	if err != nil {
+		t.Fatalf("generateNameSet failed: %v", err)
+	}
+	expected := map[string]string{
+		"azurerm_resource_group":          "contoso-rg-payments-xkcdq-prd",
+		"azurerm_key_vault":               "kv-payments-xkcdq-prd",
+		"azurerm_storage_account":         "stpaymentsxkcdqprd",
+		"azurerm_log_analytics_workspace": "contoso-log-payments-xkcdq-prd",
+		"azurerm_app_service_plan":        "contoso-plan-payments-xkcdq-linux",
+		"azurerm_linux_web_app":           "contoso-web-api-xkcdq-prd",
+	}
+	for resourceTypeName, name := range expected {
+		if results[resourceTypeName] != name {
+			t.Errorf("Expected %s for %s, got %s", name, resourceTypeName, results[resourceTypeName])
+		}
+	}
+}
+
+func TestGenerateNameSet_RandomKept(t *testing.T) {
+	// azurecaf_name drops the random part of a name too long for the resource type
+	long := "paymentsreconciliation"
+	_, results, err := generateNames(nameParameters{Name: long, ResourceTypes: []string{"azurerm_key_vault"}, Separator: "-", CleanInput: true, UseSlug: true, RandomString: "xkcdq", RandomLength: 5})
+	if err != nil || strings.Contains(results["azurerm_key_vault"], "xkcdq") {
+		t.Fatalf("Expected azurecaf_name to drop the random part, got %v (%v)", results, err)
+	}
+
+	results, err = generateNameSet(nameParameters{Name: long, ResourceTypes: []string{"azurerm_key_vault", "azurerm_storage_account"}, Separator: "-", CleanInput: true, UseSlug: true, RandomString: "xkcdq"}, nil)
+	if err != nil {
+		t.Fatalf("generateNameSet failed: %v", err)
+	}
+	for resourceTypeName, name := range results {
+		resource, _ := getResource(resourceTypeName)
+		if !strings.Contains(name, "xkcdq") || len(name) > resource.MaxLength {
+			t.Errorf("Expected the random part in a name of at most %d characters, got %s", resource.MaxLength, name)
+		}
+	}
+
+	if _, err := generateNameSet(nameParameters{Name: "app", ResourceTypes: []string{"azurerm_key_vault"}}, map[string]nameSetOverride{"azurerm_storage_account": {Name: "st"}}); err == nil {
+		t.Error("Expected an override of a resource type outside of resource_types to fail")
+	}
+}
+
+func TestResourceNameSet(t *testing.T) {
+	config := map[string]interface{}{
+		"name":           "app",
+		"resource_types": []interface{}{"azurerm_key_vault", "azurerm_storage_account"},
+		"random_length":  4,
+		"override": []interface{}{
+			map[string]interface{}{"resource_type": "azurerm_storage_account", "name": "data"},
+		},
+	}
+	rd := schema.TestResourceDataRaw(t, resourceNameSet().Schema, config)
+	if diags := resourceNameSetCreate(context.Background(), rd, nil); diags.HasError() {
+		t.Fatalf("resourceNameSetCreate failed: %v", diags[0].Summary)
+	}
+	random := rd.Get("random_string").(string)
+	results := rd.Get("results").(map[string]interface{})
+	if len(random) != 4 || results["azurerm_key_vault"] != "kv-app-"+random || results["azurerm_storage_account"] != "stdata"+random {
+		t.Errorf("Expected names sharing the random part %s, got %v", random, results)
+	}
+
+	config["override"] = append(config["override"].([]interface{}), map[string]interface{}{"resource_type": "azurerm_storage_account", "slug": "sa"})
+	data := schema.TestResourceDataRaw(t, dataNameSet().Schema, config)
+	if diags := dataNameSetRead(context.Background(), data, nil); !diags.HasError() {
+		t.Error("Expected a resource type overridden twice to fail")
+	}
+}
+
+func TestResourceNameSet_Options(t *testing.T) {
+	config := map[string]interface{}{
+		"name":               "production",
+		"resource_types":     []interface{}{"azurerm_key_vault", "azurerm_storage_account", "azurerm_resource_group"},
+		"random_length":      4,
+		"random_seed":        7,
+		"separator":          "_",
+		"separator_fallback": SeparatorFallbackAuto,
+		"uniqueness":         UniquenessAuto,
+		"abbreviate":         true,
+		"override": []interface{}{
+			map[string]interface{}{"resource_type": "azurerm_storage_account", "name": "production-reconciliation-ledger"},
+		},
+	}
+	rd := schema.TestResourceDataRaw(t, resourceNameSet().Schema, config)
+	if diags := resourceNameSetCreate(context.Background(), rd, nil); diags.HasError() {
+		t.Fatalf("resourceNameSetCreate failed: %v", diags[0].Summary)
+	}
+	random := rd.Get("random_string").(string)
+	results := rd.Get("results").(map[string]interface{})
+	for resourceTypeName, name := range results {
+		resource, _ := getResource(resourceTypeName)
+		if !strings.Contains(name.(string), random) || len(name.(string)) > resource.MaxLength {
+			t.Errorf("Expected the random part %s in a name of at most %d characters, got %s", random, resource.MaxLength, name)
+		}
+		if strings.Contains(name.(string), "production") {
+			t.Errorf("Expected production to be abbreviated, got %s", name)
+		}
+	}
+	if rg := results["azurerm_resource_group"].(string); rg != "rg_prd_"+random {
+		t.Errorf("Expected the resource group name rg_prd_%s, got %s", random, rg)
+	}
+	// The key vault does not allow _, its names have a global scope and end with the hash
+	if kv := results["azurerm_key_vault"].(string); strings.Contains(kv, "_") || !strings.HasPrefix(kv, "kvPrd") || len(kv) != len("kvPrd")+len(random)+uniquenessHashLength {
+		t.Errorf("Expected a camelCase key vault name ending with the uniqueness hash, got %s", kv)
+	}
+	if abbreviations := rd.Get("abbreviations").(map[string]interface{}); abbreviations["production"] != "prd" {
+		t.Errorf("Expected production to be recorded as abbreviated, got %v", abbreviations)
+	}
+
+	// The conventions drawing the random part of each name are rejected
+	for _, convention := range []string{ConventionCafRandom, ConventionRandom, ConventionPassThrough} {
+		config["convention"] = convention
+		data := schema.TestResourceDataRaw(t, dataNameSet().Schema, config)
+		if diags := dataNameSetRead(context.Background(), data, nil); !diags.HasError() || !strings.Contains(diags[0].Summary, "not supported") {
+			t.Errorf("Expected convention %s to be rejected, got %v", convention, diags)
+		}
+	}
+}
+
+func TestResourceNameSet_Ledger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.json")
+	config := map[string]interface{}{
+		"name":           "app",
+		"resource_types": []interface{}{"azurerm_key_vault", "azurerm_storage_account"},
+	}
+
+	landingZone := testLedgerMeta(t, path, "landing-zone", LedgerCollisionReject)
+	first := schema.TestResourceDataRaw(t, resourceNameSet().Schema, config)
+	if diags := resourceNameSetCreate(context.Background(), first, landingZone); diags.HasError() {
+		t.Fatalf("resourceNameSetCreate failed: %v", diags[0].Summary)
+	}
+
+	// The names of the set are reserved like those of azurecaf_name
+	application := testLedgerMeta(t, path, "application", LedgerCollisionReject)
+	name := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{"name": "app", "resource_type": "azurerm_key_vault"})
+	if diags := resourceNameCreate(context.Background(), name, application); !diags.HasError() || !strings.Contains(diags[0].Summary, "kv-app") {
+		t.Errorf("Expected the name reserved by the set to be rejected, got %v", diags)
+	}
+
+	if err := resourceNameDelete(first, landingZone); err != nil {
+		t.Fatalf("resourceNameDelete failed: %v", err)
+	}
+	if diags := resourceNameCreate(context.Background(), name, application); diags.HasError() {
+		t.Errorf("Expected the released name to be free, got %v", diags)
+	}
+}
+
+func TestResourceNameSet_Duplicates(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{"on_duplicate_name": DuplicateNameWarn})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("providerConfigure failed: %v", diags)
+	}
+	config := map[string]interface{}{
+		"name":           "app",
+		"resource_types": []interface{}{"azurerm_key_vault"},
+		"scope_key":      "rg-app",
+	}
+	if diags := resourceNameSetCreate(context.Background(), schema.TestResourceDataRaw(t, resourceNameSet().Schema, config), meta); len(diags) != 0 {
+		t.Fatalf("Expected no diagnostic, got %v", diags)
+	}
+	name := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{"name": "app", "resource_type": "azurerm_key_vault", "scope_key": "rg-app"})
+	diags = resourceNameCreate(context.Background(), name, meta)
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, "name kv-app of azurerm_key_vault is generated more than once") {
+		t.Errorf("Expected the name of the set to be reported as a duplicate, got %v", diags)
+	}
+}
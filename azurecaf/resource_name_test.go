@@ -2,12 +2,17 @@ package azurecaf
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func setData(prefixes []string, name string, suffixes []string, cleanInput bool) *schema.ResourceData {
@@ -22,7 +27,10 @@ func setData(prefixes []string, name string, suffixes []string, cleanInput bool)
 func TestCleanInput_no_changes(t *testing.T) {
 	data := "testdata"
 	resource := ResourceDefinitions["azurerm_resource_group"]
-	result := cleanString(data, &resource)
+	result, err := cleanString(data, &resource)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if data != result {
 		t.Errorf("Expected %s but received %s", data, result)
 	}
@@ -32,7 +40,10 @@ func TestCleanInput_remove_always(t *testing.T) {
 	data := "🐱‍🚀testdata😊"
 	expected := "testdata"
 	resource := ResourceDefinitions["azurerm_resource_group"]
-	result := cleanString(data, &resource)
+	result, err := cleanString(data, &resource)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if result != expected {
 		t.Errorf("Expected %s but received %s", expected, result)
 	}
@@ -42,7 +53,10 @@ func TestCleanInput_not_remove_special_allowed_chars(t *testing.T) {
 	data := "testdata()"
 	expected := "testdata()"
 	resource := ResourceDefinitions["azurerm_resource_group"]
-	result := cleanString(data, &resource)
+	result, err := cleanString(data, &resource)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if result != expected {
 		t.Errorf("Expected %s but received %s", expected, result)
 	}
@@ -51,7 +65,10 @@ func TestCleanInput_not_remove_special_allowed_chars(t *testing.T) {
 func TestCleanSplice_no_changes(t *testing.T) {
 	data := []string{"testdata", "test", "data"}
 	resource := ResourceDefinitions["azurerm_resource_group"]
-	result := cleanSlice(data, &resource)
+	result, err := cleanSlice(data, &resource)
+	if err != nil {
+		t.Fatal(err)
+	}
 	for i := range data {
 		if data[i] != result[i] {
 			t.Errorf("Expected %s but received %s", data[i], result[i])
@@ -110,9 +127,9 @@ func TestAccResourceName_CafClassic(t *testing.T) {
 			"clean_input":   true,
 		})
 
-		err := nameResource.Create(resourceData, nil)
-		if err != nil {
-			t.Fatalf("Failed to create resource: %v", err)
+		diags := nameResource.CreateContext(context.Background(), resourceData, nil)
+		if diags.HasError() {
+			t.Fatalf("Failed to create resource: %v", diags)
 		}
 
 		result := resourceData.Get("result").(string)
@@ -137,9 +154,9 @@ func TestAccResourceName_CafClassic(t *testing.T) {
 			"clean_input":   true,
 		})
 
-		err := nameResource.Create(resourceData, nil)
-		if err != nil {
-			t.Fatalf("Failed to create resource: %v", err)
+		diags := nameResource.CreateContext(context.Background(), resourceData, nil)
+		if diags.HasError() {
+			t.Fatalf("Failed to create resource: %v", diags)
 		}
 
 		result := resourceData.Get("result").(string)
@@ -167,9 +184,9 @@ func TestAccResourceName_CafClassic(t *testing.T) {
 			"passthrough":   true,
 		})
 
-		err := nameResource.Create(resourceData, nil)
-		if err != nil {
-			t.Fatalf("Failed to create resource: %v", err)
+		diags := nameResource.CreateContext(context.Background(), resourceData, nil)
+		if diags.HasError() {
+			t.Fatalf("Failed to create resource: %v", diags)
 		}
 
 		result := resourceData.Get("result").(string)
@@ -189,9 +206,9 @@ func TestAccResourceName_CafClassic(t *testing.T) {
 			"clean_input":   true,
 		})
 
-		err := nameResource.Create(resourceData, nil)
-		if err != nil {
-			t.Fatalf("Failed to create resource: %v", err)
+		diags := nameResource.CreateContext(context.Background(), resourceData, nil)
+		if diags.HasError() {
+			t.Fatalf("Failed to create resource: %v", diags)
 		}
 
 		result := resourceData.Get("result").(string)
@@ -218,9 +235,9 @@ func TestAccResourceName_CafClassic(t *testing.T) {
 			"clean_input":   true,
 		})
 
-		err := nameResource.Create(resourceData, nil)
-		if err != nil {
-			t.Fatalf("Failed to create resource: %v", err)
+		diags := nameResource.CreateContext(context.Background(), resourceData, nil)
+		if diags.HasError() {
+			t.Fatalf("Failed to create resource: %v", diags)
 		}
 
 		result := resourceData.Get("result").(string)
@@ -258,9 +275,9 @@ func TestAccResourceName_CafClassicRSV(t *testing.T) {
 			"passthrough":   false,
 		})
 
-		err := nameResource.Create(resourceData, nil)
-		if err != nil {
-			t.Fatalf("Failed to create resource: %v", err)
+		diags := nameResource.CreateContext(context.Background(), resourceData, nil)
+		if diags.HasError() {
+			t.Fatalf("Failed to create resource: %v", diags)
 		}
 
 		result := resourceData.Get("result").(string)
@@ -463,6 +480,84 @@ func TestResourceExampleInstanceStateUpgradeV2(t *testing.T) {
 	}
 }
 
+func TestResourceExampleInstanceStateUpgradeV3(t *testing.T) {
+	nameResource := resourceName()
+	rawState := map[string]interface{}{
+		"id":            "rg-myapp",
+		"name":          "myapp",
+		"resource_type": "azurerm_resource_group",
+		"result":        "rg-myapp",
+		"separator":     "-",
+		"clean_input":   true,
+		"passthrough":   false,
+		"use_slug":      true,
+		"random_length": 0,
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"name": "myapp", "resource_type": "azurerm_resource_group"})
+	instanceState := func(rawState map[string]interface{}) *terraform.InstanceState {
+		attributes := map[string]string{"prefixes.#": "0", "suffixes.#": "0", "resource_types.#": "0", "results.%": "0"}
+		for key, value := range rawState {
+			attributes[key] = fmt.Sprint(value)
+		}
+		return &terraform.InstanceState{ID: "rg-myapp", Attributes: attributes}
+	}
+
+	// Without the upgrade, the defaults of the instance numbering replace the resource
+	diff, err := nameResource.Diff(context.Background(), instanceState(rawState), config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || !diff.RequiresNew() {
+		t.Fatal("Expected a version 3 state to be replaced without upgrade")
+	}
+
+	upgraded, err := resourceNameStateUpgradeV3(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+	if upgraded["instance_start"] != defaultInstanceStart || upgraded["instance_padding"] != defaultInstancePadding || upgraded["instance_count"] != 0 {
+		t.Errorf("Unexpected upgraded state %#v", upgraded)
+	}
+
+	// The next plan of an upgraded state changes nothing
+	server := GRPCProviderServer()
+	tests := map[string]struct {
+		version int64
+		state   string
+		config  map[string]cty.Value
+	}{
+		"version 3": {3, `{"id": "rg-myapp", "name": "myapp", "prefixes": [], "suffixes": [], "random_length": 0, "result": "rg-myapp", "results": {},
+			"separator": "-", "clean_input": true, "passthrough": false, "resource_type": "azurerm_resource_group", "resource_types": [], "random_seed": null, "use_slug": true}`,
+			map[string]cty.Value{"name": cty.StringVal("myapp"), "resource_type": cty.StringVal("azurerm_resource_group")}},
+		"version 3 with resource types": {3, `{"id": "abcdefghijklmnop", "name": "app", "prefixes": ["dev"], "suffixes": [], "random_length": 5, "result": "stdevappxvlbz",
+			"results": {"azurerm_key_vault": "kv-dev-app-xvlbz"}, "separator": "-", "clean_input": true, "passthrough": false, "resource_type": "azurerm_storage_account",
+			"resource_types": ["azurerm_key_vault"], "random_seed": 123, "use_slug": true}`,
+			map[string]cty.Value{
+				"name":           cty.StringVal("app"),
+				"prefixes":       cty.ListVal([]cty.Value{cty.StringVal("dev")}),
+				"random_length":  cty.NumberIntVal(5),
+				"random_seed":    cty.NumberIntVal(123),
+				"resource_type":  cty.StringVal("azurerm_storage_account"),
+				"resource_types": cty.ListVal([]cty.Value{cty.StringVal("azurerm_key_vault")}),
+			}},
+		"version 2": {2, `{"id": "rg-myapp", "name": "myapp", "prefixes": [], "suffixes": [], "random_length": 0, "result": "rg-myapp", "results": {},
+			"separator": "-", "clean_input": true, "passthrough": false, "resource_type": "azurerm_resource_group", "resource_types": [], "random_seed": null}`,
+			map[string]cty.Value{"name": cty.StringVal("myapp"), "resource_type": cty.StringVal("azurerm_resource_group")}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := upgradeNameState(t, server, tt.version, tt.state)
+			checkEmptyNamePlan(t, server, state, tt.config)
+		})
+	}
+
+	// Values of states written since are kept
+	upgraded, _ = resourceNameStateUpgradeV3(context.Background(), map[string]interface{}{"instance_start": 0}, nil)
+	if upgraded["instance_start"] != 0 {
+		t.Errorf("Expected instance_start to be kept, got %v", upgraded["instance_start"])
+	}
+}
+
 const testAccResourceNameCafClassicConfig = `
 
 
@@ -541,3 +636,206 @@ resource "azurecaf_name" "rsv" {
 	passthrough     = false
 }
 `
+
+func TestResourceNameRead_Drift(t *testing.T) {
+	nameResource := resourceName()
+	newResourceData := func(recreate bool) *schema.ResourceData {
+		resourceData := schema.TestResourceDataRaw(t, nameResource.Schema, map[string]interface{}{
+			"name":              "myapp",
+			"resource_type":     "azurerm_resource_group",
+			"resource_types":    []interface{}{"azurerm_storage_account"},
+			"recreate_on_drift": recreate,
+		})
+		if diags := nameResource.CreateContext(context.Background(), resourceData, nil); diags.HasError() {
+			t.Fatalf("Create failed: %v", diags)
+		}
+		return resourceData
+	}
+
+	// Names generated by the current definitions do not drift
+	resourceData := newResourceData(false)
+	if diags := resourceNameRead(context.Background(), resourceData, nil); len(diags) != 0 {
+		t.Errorf("Expected no drift, got %v", diags)
+	}
+	if resourceData.Get("drift_detected").(bool) {
+		t.Error("Expected drift_detected to be false")
+	}
+
+	// A stored name that no longer matches the definition is reported
+	resourceData.Set("results", map[string]string{"azurerm_storage_account": "St-MyApp"})
+	diags := resourceNameRead(context.Background(), resourceData, nil)
+	if len(diags) == 0 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Summary, "St-MyApp") {
+		t.Errorf("Expected a warning for the invalid stored name, got %v", diags)
+	}
+	if !resourceData.Get("drift_detected").(bool) || resourceData.Id() == "" {
+		t.Error("Expected the drift to be flagged without removing the resource")
+	}
+
+	// A valid name that would be generated differently is reported too
+	resourceData = newResourceData(true)
+	resourceData.Set("result", "rg-otherapp")
+	diags = resourceNameRead(context.Background(), resourceData, nil)
+	if len(diags) != 1 || !strings.Contains(diags[0].Detail, "rg-myapp") {
+		t.Errorf("Expected a warning with the regenerated name, got %v", diags)
+	}
+	if resourceData.Id() != "" {
+		t.Error("Expected recreate_on_drift to remove the resource from the state")
+	}
+
+	// An update reports the drift but keeps the resource, removing it fails the apply
+	state := newResourceData(true).State()
+	state.Attributes["result"] = "rg-otherapp"
+	resourceData = nameResource.Data(state)
+	diags = resourceNameUpdate(context.Background(), resourceData, nil)
+	if len(diags) != 1 || !resourceData.Get("drift_detected").(bool) {
+		t.Errorf("Expected the update to report the drift, got %v", diags)
+	}
+	if resourceData.Id() == "" {
+		t.Error("Expected the update to keep the resource in the state")
+	}
+}
+
+func TestResourceName_Keepers(t *testing.T) {
+	nameResource := resourceName()
+	ctx := context.Background()
+
+	create := func(raw map[string]interface{}) *terraform.InstanceState {
+		resourceData := schema.TestResourceDataRaw(t, nameResource.Schema, raw)
+		if diags := nameResource.CreateContext(context.Background(), resourceData, nil); diags.HasError() {
+			t.Fatalf("Create failed: %v", diags)
+		}
+		return resourceData.State()
+	}
+	config := map[string]interface{}{
+		"name":          "myapp",
+		"resource_type": "azurerm_resource_group",
+		"random_length": 5,
+		"keepers":       map[string]interface{}{"build": "1"},
+	}
+	state := create(config)
+	randomString := state.Attributes["random_string"]
+	if len(randomString) != 5 || state.Attributes["result"] != "rg-myapp-"+randomString {
+		t.Fatalf("Unexpected state %v", state.Attributes)
+	}
+
+	// Changing a naming argument composes the name again with the stored random part
+	config["suffixes"] = []interface{}{"001"}
+	diff, err := nameResource.Diff(ctx, state, terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if diff.RequiresNew() {
+		t.Error("Expected the suffix change to update the name in place")
+	}
+	if expected := "rg-myapp-" + randomString + "-001"; diff.Attributes["result"] == nil || diff.Attributes["result"].New != expected {
+		t.Errorf("Expected the planned result to be %s, got %v", expected, diff.Attributes["result"])
+	}
+	updated, diags := nameResource.Apply(ctx, state, diff, nil)
+	if diags.HasError() {
+		t.Fatalf("Apply failed: %v", diags)
+	}
+	if updated.Attributes["random_string"] != randomString || updated.ID != state.ID {
+		t.Errorf("Expected the random part and the id to be kept, got %v", updated.Attributes)
+	}
+
+	// Changing keepers generates a new random part
+	config["keepers"] = map[string]interface{}{"build": "2"}
+	diff, err = nameResource.Diff(ctx, updated, terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !diff.RequiresNew() {
+		t.Error("Expected a keepers change to replace the name")
+	}
+
+	// Without keepers, naming arguments keep replacing the resource
+	delete(config, "keepers")
+	delete(config, "suffixes")
+	state = create(config)
+	config["suffixes"] = []interface{}{"001"}
+	diff, err = nameResource.Diff(ctx, state, terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !diff.RequiresNew() {
+		t.Error("Expected a suffix change without keepers to replace the name")
+	}
+}
+
+func TestResourceName_Rotation(t *testing.T) {
+	nameResource := resourceName()
+	ctx := context.Background()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer func() { clock = time.Now }()
+
+	config := map[string]interface{}{
+		"name":          "myapp",
+		"resource_type": "azurerm_resource_group",
+		"random_length": 5,
+		"rotation_days": 30,
+	}
+	clock = func() time.Time { return created }
+	resourceData := schema.TestResourceDataRaw(t, nameResource.Schema, config)
+	if diags := nameResource.CreateContext(context.Background(), resourceData, nil); diags.HasError() {
+		t.Fatalf("Create failed: %v", diags)
+	}
+	state := resourceData.State()
+	if state.Attributes["created_at"] != "2026-01-01T00:00:00Z" {
+		t.Fatalf("Unexpected created_at %s", state.Attributes["created_at"])
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		rotateAt string
+		replace  bool
+	}{
+		{"within the rotation window", created.AddDate(0, 0, 29), "", false},
+		{"rotation window passed", created.AddDate(0, 0, 30), "", true},
+		{"rotate_at reached", created.AddDate(0, 0, 10), "2026-01-05T00:00:00Z", true},
+		{"rotate_at in the future", created.AddDate(0, 0, 10), "2026-01-20T00:00:00Z", false},
+		{"created after rotate_at", created.AddDate(0, 0, 10), "2025-12-01T00:00:00Z", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock = func() time.Time { return tt.now }
+			raw := map[string]interface{}{}
+			for key, value := range config {
+				raw[key] = value
+			}
+			if tt.rotateAt != "" {
+				raw["rotate_at"] = tt.rotateAt
+			}
+			diff, err := nameResource.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), nil)
+			if err != nil {
+				t.Fatalf("Diff failed: %v", err)
+			}
+			if replace := diff != nil && diff.RequiresNew(); replace != tt.replace {
+				t.Errorf("Expected replacement %v, got %v", tt.replace, replace)
+			}
+		})
+	}
+}
+
+func TestResourceName_RotationWithoutRandomPart(t *testing.T) {
+	nameResource := resourceName()
+	tests := map[string]map[string]interface{}{
+		"without random part": {"random_length": 0, "rotation_days": 30},
+		"with random_seed":    {"random_length": 5, "random_seed": 42, "rotate_at": "2026-01-05T00:00:00Z"},
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw["name"], raw["resource_type"] = "myapp", "azurerm_resource_group"
+			_, err := nameResource.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+			if err == nil || !strings.Contains(err.Error(), "rotation_days and rotate_at") {
+				t.Errorf("Expected the rotation to be rejected, got %v", err)
+			}
+		})
+	}
+
+	// The cafrandom convention draws its own random part
+	raw := map[string]interface{}{"name": "myapp", "resource_type": "azurerm_resource_group", "convention": "cafrandom", "rotation_days": 30}
+	if _, err := nameResource.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
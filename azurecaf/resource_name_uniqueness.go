@@ -0,0 +1,91 @@
+package azurecaf
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Uniqueness strategies of azurecaf_name.
+const (
+	// UniquenessNone never adds a hash to the names.
+	UniquenessNone = "none"
+	// UniquenessAuto adds a hash to the names of the resource types whose names must be
+	// unique across Azure.
+	UniquenessAuto = "auto"
+)
+
+// ScopeGlobal is the scope of the resource types whose names must be unique across Azure.
+const ScopeGlobal = "global"
+
+// uniquenessHashLength is the number of characters of the uniqueness hash.
+const uniquenessHashLength = 5
+
+// uniquenessHash returns the hash added to the name of resource with uniqueness, or an
+// empty string when the scope of the resource does not need one. The hash only depends
+// on the seed, the resource type and the components of the name, so the same inputs
+// always give the same name. The seed, e.g. a subscription ID, gives other names to
+// the same components in other deployments.
+func uniquenessHash(resource *ResourceStructure, uniqueness string, seed string, inputs nameInputs) string {
+	if uniqueness != UniquenessAuto || resource.Scope != ScopeGlobal {
+		return ""
+	}
+	hashInput := fmt.Sprintf("%s %q %q %q", resource.ResourceTypeName, inputs.Prefixes, inputs.Name, inputs.Suffixes)
+	if seed != "" {
+		hashInput = fmt.Sprintf("%q %s", seed, hashInput)
+	}
+	sum := sha256.Sum256([]byte(hashInput))
+	hash := make([]rune, uniquenessHashLength)
+	for i := range hash {
+		hash[i] = alphagenerator[int(sum[i])%len(alphagenerator)]
+	}
+	return string(hash)
+}
+
+// withUniquenessHash sets the uniqueness hash of inputs. Like the instance number, the
+// hash ends the name and is never dropped: the other components share the room it leaves.
+// The hash stored for the resource type is reused, so that composing the names again
+// with keepers, e.g. after a change of the suffixes, keeps their hash like their random
+// part.
+func withUniquenessHash(resource *ResourceStructure, params nameParameters, inputs nameInputs) nameInputs {
+	inputs.Hash = uniquenessHash(resource, params.Uniqueness, params.UniquenessSeed, inputs)
+	if stored := params.UniquenessHashes[resource.ResourceTypeName]; inputs.Hash != "" && stored != "" {
+		inputs.Hash = stored
+	}
+	if inputs.Hash != "" && params.Hashes != nil {
+		params.Hashes[resource.ResourceTypeName] = inputs.Hash
+	}
+	return inputs
+}
+
+// nameUniquenessHashes returns the uniqueness hash of each resource type of params,
+// for the states written before the hashes were stored.
+func nameUniquenessHashes(params nameParameters) map[string]string {
+	params.Hashes = map[string]string{}
+	params.Replacements = nil
+	if _, _, err := generateNames(params); err != nil {
+		return map[string]string{}
+	}
+	return params.Hashes
+}
+
+// nameScopes returns the scope of each resource type of params.
+func nameScopes(params nameParameters) map[string]string {
+	scopes := make(map[string]string, len(params.ResourceTypes)+1)
+	for _, resourceTypeName := range append([]string{params.ResourceType}, params.ResourceTypes...) {
+		if resourceTypeName == "" {
+			continue
+		}
+		if resource, err := getResource(resourceTypeName); err == nil {
+			scopes[resourceTypeName] = resource.Scope
+		}
+	}
+	return scopes
+}
+
+func setNameScopes(d *schema.ResourceData, params nameParameters) {
+	scopes := nameScopes(params)
+	d.Set("scope", scopes[params.ResourceType])
+	d.Set("scopes", scopes)
+}
@@ -0,0 +1,237 @@
+package azurecaf
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestUniqueness_Names(t *testing.T) {
+	params := nameParameters{
+		Name:          "myapp",
+		Prefixes:      []string{"dev"},
+		Separator:     "-",
+		ResourceType:  "azurerm_storage_account",
+		ResourceTypes: []string{"azurerm_key_vault", "azurerm_subnet", "azurerm_resource_group"},
+		CleanInput:    true,
+		UseSlug:       true,
+		Uniqueness:    UniquenessAuto,
+	}
+	result, results, err := generateNames(params)
+	if err != nil {
+		t.Fatalf("generateNames failed: %v", err)
+	}
+	expected := map[string]string{
+		"azurerm_storage_account": "devstmyappukczw",
+		"azurerm_key_vault":       "dev-kv-myapp-tooeg",
+		"azurerm_subnet":          "dev-snet-myapp",
+		"azurerm_resource_group":  "dev-rg-myapp",
+	}
+	results["azurerm_storage_account"] = result
+	for resourceType, name := range expected {
+		if results[resourceType] != name {
+			t.Errorf("Expected %s for %s, got %s", name, resourceType, results[resourceType])
+		}
+	}
+
+	// The hash follows the components of the name
+	params.Name = "otherapp"
+	if other, _, err := generateNames(params); err != nil || other[len(other)-uniquenessHashLength:] == "ukczw" {
+		t.Errorf("Expected another hash for another name, got %s (%v)", other, err)
+	}
+
+	params.Name = "myapp"
+	params.Uniqueness = UniquenessNone
+	if result, _, err := generateNames(params); err != nil || result != "devstmyapp" {
+		t.Errorf("Expected no hash without uniqueness, got %s (%v)", result, err)
+	}
+}
+
+func TestUniqueness_AllResourceTypes(t *testing.T) {
+	for _, convention := range []string{ConventionCafClassic, ConventionCafRandom, ConventionRandom} {
+		for resourceType, resource := range ResourceDefinitions {
+			result, _, err := generateNames(nameParameters{
+				Name:              "myapp",
+				Prefixes:          []string{"dev"},
+				Separator:         "-",
+				ResourceType:      resourceType,
+				CleanInput:        true,
+				UseSlug:           true,
+				RandomSeed:        42,
+				BuiltinConvention: convention,
+				Uniqueness:        UniquenessAuto,
+			})
+			if err != nil {
+				t.Errorf("%s %s: %v", convention, resourceType, err)
+				continue
+			}
+			if !regexp.MustCompile(resource.ValidationRegExp).MatchString(result) {
+				t.Errorf("%s %s: %s does not match %s", convention, resourceType, result, resource.ValidationRegExp)
+			}
+		}
+	}
+}
+
+func TestUniqueness_Scopes(t *testing.T) {
+	rd := schema.TestResourceDataRaw(t, resourceName().Schema, map[string]interface{}{
+		"name":           "myapp",
+		"resource_type":  "azurerm_key_vault",
+		"resource_types": []interface{}{"azurerm_subnet"},
+		"uniqueness":     UniquenessAuto,
+	})
+	if _, err := getNameResult(rd, nil); err != nil {
+		t.Fatalf("getNameResult failed: %v", err)
+	}
+	if result := rd.Get("result").(string); result != "kv-myapp-vltcu" {
+		t.Errorf("Unexpected key vault name %s", result)
+	}
+	if scope := rd.Get("scope").(string); scope != ScopeGlobal {
+		t.Errorf("Expected the global scope, got %s", scope)
+	}
+	if scope := rd.Get("scopes").(map[string]interface{})["azurerm_subnet"]; scope != "parent" {
+		t.Errorf("Expected the parent scope for azurerm_subnet, got %v", scope)
+	}
+
+	data := schema.TestResourceDataRaw(t, dataName().Schema, map[string]interface{}{
+		"name":          "myapp",
+		"resource_type": "azurerm_key_vault",
+		"uniqueness":    UniquenessAuto,
+	})
+	if diags := dataNameRead(context.Background(), data, nil); diags.HasError() {
+		t.Fatalf("dataNameRead failed: %v", diags)
+	}
+	if result := data.Get("result").(string); result != rd.Get("result").(string) {
+		t.Errorf("Expected the data source to generate %s, got %s", rd.Get("result").(string), result)
+	}
+	if scope := data.Get("scope").(string); scope != ScopeGlobal {
+		t.Errorf("Expected the data source to report the global scope, got %s", scope)
+	}
+}
+
+func TestUniqueness_LengthLimit(t *testing.T) {
+	params := nameParameters{
+		Name:         "contosofinanceapp",
+		Separator:    "-",
+		ResourceType: "azurerm_key_vault",
+		CleanInput:   true,
+		UseSlug:      true,
+		RandomString: "xyz",
+		RandomLength: 3,
+		Uniqueness:   UniquenessAuto,
+	}
+	for _, resourceType := range []string{"azurerm_key_vault", "azurerm_storage_account"} {
+		params.ResourceType = resourceType
+		result, _, err := generateNames(params)
+		if err != nil {
+			t.Fatalf("generateNames failed for %s: %v", resourceType, err)
+		}
+		resource := ResourceDefinitions[resourceType]
+		hash := uniquenessHash(&resource, UniquenessAuto, "", nameInputs{Name: "contosofinanceapp"})
+		if len(result) > resource.MaxLength || !strings.HasSuffix(result, hash) {
+			t.Errorf("Expected %s to end with the hash %s within %d characters", result, hash, resource.MaxLength)
+		}
+	}
+
+	// The hash is kept before the instance number
+	params.ResourceType = "azurerm_storage_account"
+	params.Instance = "001"
+	result, _, err := generateNames(params)
+	if err != nil {
+		t.Fatalf("generateNames failed: %v", err)
+	}
+	resource := ResourceDefinitions["azurerm_storage_account"]
+	hash := uniquenessHash(&resource, UniquenessAuto, "", nameInputs{Name: "contosofinanceapp"})
+	if len(result) > resource.MaxLength || !strings.HasSuffix(result, hash+"001") {
+		t.Errorf("Expected %s to end with the hash %s and the instance", result, hash)
+	}
+}
+
+func TestUniqueness_Seed(t *testing.T) {
+	params := nameParameters{
+		Name:         "myapp",
+		Separator:    "-",
+		ResourceType: "azurerm_storage_account",
+		CleanInput:   true,
+		UseSlug:      true,
+		Uniqueness:   UniquenessAuto,
+	}
+	first, _, err := generateNames(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params.UniquenessSeed = "00000000-0000-0000-0000-000000000001"
+	seeded, _, err := generateNames(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params.UniquenessSeed = "00000000-0000-0000-0000-000000000002"
+	other, _, err := generateNames(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == seeded || seeded == other {
+		t.Errorf("Expected the seed to change the hash, got %s, %s and %s", first, seeded, other)
+	}
+	if again, _, _ := generateNames(params); again != other {
+		t.Errorf("Expected the same seed to give the same name, got %s and %s", other, again)
+	}
+}
+
+func TestUniqueness_Keepers(t *testing.T) {
+	nameResource := resourceName()
+	ctx := context.Background()
+
+	config := map[string]interface{}{
+		"name":           "myapp",
+		"resource_type":  "azurerm_storage_account",
+		"resource_types": []interface{}{"azurerm_key_vault"},
+		"uniqueness":     UniquenessAuto,
+		"keepers":        map[string]interface{}{"build": "1"},
+	}
+	resourceData := schema.TestResourceDataRaw(t, nameResource.Schema, config)
+	if diags := nameResource.CreateContext(ctx, resourceData, nil); diags.HasError() {
+		t.Fatalf("Create failed: %v", diags)
+	}
+	state := resourceData.State()
+	storageHash := state.Attributes["uniqueness_hashes.azurerm_storage_account"]
+	keyVaultHash := state.Attributes["uniqueness_hashes.azurerm_key_vault"]
+	if len(storageHash) != uniquenessHashLength || state.Attributes["result"] != "stmyapp"+storageHash {
+		t.Fatalf("Unexpected state %v", state.Attributes)
+	}
+
+	// Composing the names again in place keeps their hash, like their random part
+	config["suffixes"] = []interface{}{"001"}
+	diff, err := nameResource.Diff(ctx, state, terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if diff.RequiresNew() {
+		t.Error("Expected the suffix change to update the names in place")
+	}
+	if expected := "stmyapp001" + storageHash; diff.Attributes["result"] == nil || diff.Attributes["result"].New != expected {
+		t.Errorf("Expected the planned result to be %s, got %v", expected, diff.Attributes["result"])
+	}
+	updated, diags := nameResource.Apply(ctx, state, diff, nil)
+	if diags.HasError() {
+		t.Fatalf("Apply failed: %v", diags)
+	}
+	if updated.Attributes["result"] != "stmyapp001"+storageHash || !strings.HasSuffix(updated.Attributes["results.azurerm_key_vault"], keyVaultHash) {
+		t.Errorf("Expected the hashes to be kept, got %v", updated.Attributes)
+	}
+	if updated.Attributes["uniqueness_hashes.azurerm_storage_account"] != storageHash || updated.Attributes["uniqueness_hashes.azurerm_key_vault"] != keyVaultHash {
+		t.Errorf("Expected the stored hashes to be kept, got %v", updated.Attributes)
+	}
+
+	// A new resource hashes its own inputs
+	replaced := schema.TestResourceDataRaw(t, nameResource.Schema, config)
+	if diags := nameResource.CreateContext(ctx, replaced, nil); diags.HasError() {
+		t.Fatalf("Create failed: %v", diags)
+	}
+	if hashes := replaced.Get("uniqueness_hashes").(map[string]interface{}); hashes["azurerm_storage_account"] == storageHash {
+		t.Errorf("Expected the suffixes to change the hash of a new name, got %s", storageHash)
+	}
+}
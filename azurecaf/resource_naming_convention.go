@@ -3,8 +3,6 @@ package azurecaf
 import (
 	"fmt"
 	"log"
-	"math/rand"
-	"regexp"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -22,21 +20,25 @@ import (
 //   - random: Fully random naming within Azure constraints
 //   - passthrough: Validation-only mode for existing names
 //
+// The short and long names of the original resource (st, kv, rg, ...) keep their
+// naming rules, and every type of the generated catalog is accepted as well.
+//
 // Deprecated: Use azurecaf_name resource instead for new implementations.
 func resourceNamingConvention() *schema.Resource {
-	resourceMapsKeys := make([]string, 0, len(Resources))
-	for k := range Resources {
+	resourceMapsKeys := make([]string, 0, len(legacyResourceTypes)+len(ResourceDefinitions))
+	for k := range legacyResourceTypes {
 		resourceMapsKeys = append(resourceMapsKeys, k)
 	}
-	for k := range ResourcesMapping {
+	for k := range ResourceDefinitions {
 		resourceMapsKeys = append(resourceMapsKeys, k)
 	}
 
 	return &schema.Resource{
-		Create:        resourceNamingConventionCreate,
-		Read:          schema.Noop,
-		Delete:        schema.RemoveFromState,
-		SchemaVersion: 2,
+		Create:             resourceNamingConventionCreate,
+		Read:               schema.Noop,
+		Delete:             schema.RemoveFromState,
+		SchemaVersion:      2,
+		DeprecationMessage: "azurecaf_naming_convention is deprecated, use azurecaf_name instead. Existing resources can be moved to azurecaf_name with a moved block without changing their names.",
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -89,6 +91,11 @@ func resourceNamingConvention() *schema.Resource {
 				ForceNew:     true,
 				ValidateFunc: validation.IntAtLeast(1),
 			},
+			"random_seed": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
 			"result": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -122,16 +129,14 @@ func getResult(d *schema.ResourceData, meta interface{}) error {
 	resourceType := d.Get("resource_type").(string)
 	convention := d.Get("convention").(string)
 	desiredMaxLength := d.Get("max_length").(int)
+	// all the random characters come from one source so that seeded results can be reproduced
+	random := newRandomSource(int64(d.Get("random_seed").(int)))
 
 	// Load the regular expression based on the resource type
 	var regExFilter string
-	var resource ResourceStructure
-	var resourceFound bool = false
-	if resource, resourceFound = Resources[resourceType]; !resourceFound {
-		resource, resourceFound = ResourcesMapping[resourceType]
-	}
-	if !resourceFound {
-		return fmt.Errorf("Invalid resource type %s", resourceType)
+	resource, err := getLegacyResource(resourceType)
+	if err != nil {
+		return err
 	}
 
 	regExFilter = string(resource.RegEx)
@@ -139,7 +144,7 @@ func getResult(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("%s", regExFilter)
 
 	var cafPrefix string
-	var randomSuffix string = randSeq(int(resource.MaxLength), nil)
+	var randomSuffix string = randSeqFrom(random, int(resource.MaxLength))
 
 	// configuring the prefix, cafprefix, name, postfix depending on the naming convention
 	switch convention {
@@ -152,8 +157,14 @@ func getResult(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	// joning the elements performing first filter to remove non compatible characters based on the resource type
-	myRegex, _ := regexp.Compile(regExFilter)
-	validationRegEx, _ := regexp.Compile(validationRegExPattern)
+	myRegex, err := compileRegexp(regExFilter)
+	if err != nil {
+		return fmt.Errorf("invalid cleaning regular expression of %s: %w", resourceType, err)
+	}
+	validationRegEx, err := compileRegexp(validationRegExPattern)
+	if err != nil {
+		return fmt.Errorf("invalid validation regular expression of %s: %w", resourceType, err)
+	}
 	// clear the name first based on the regexp filter of the resource type
 	nameList := []string{}
 	for _, s := range []string{prefix, cafPrefix, name, postfix} {
@@ -206,7 +217,7 @@ func getResult(d *schema.ResourceData, meta interface{}) error {
 	result := string(filteredGeneratedName[0:length])
 	// making sure the last char is alpha char if we included random string
 	if containsRandomChar && len(result) > len(userInputName) {
-		randomLastChar := alphagenerator[rand.Intn(len(alphagenerator)-1)]
+		randomLastChar := alphagenerator[random.Intn(len(alphagenerator)-1)]
 		resultRune := []rune(result)
 		resultRune[len(resultRune)-1] = randomLastChar
 		result = string(resultRune)
@@ -226,3 +237,86 @@ func getResult(d *schema.ResourceData, meta interface{}) error {
 	d.SetId(randSeq(16, nil))
 	return nil
 }
+
+// legacyResourceTypes translates the resource types of the original
+// azurecaf_naming_convention resource into ResourceDefinitions keys.
+var legacyResourceTypes = map[string]string{
+	"aaa":    "azurerm_automation_account",
+	"ac":     "azurerm_container_app",
+	"ace":    "azurerm_container_app_environment",
+	"acr":    "azurerm_container_registry",
+	"afw":    "azurerm_firewall",
+	"agw":    "azurerm_application_gateway",
+	"aks":    "azurerm_kubernetes_cluster",
+	"aksnpl": "aks_node_pool_linux",
+	"aksnpw": "aks_node_pool_windows",
+	"apim":   "azurerm_api_management",
+	"app":    "azurerm_app_service",
+	"appi":   "azurerm_application_insights",
+	"ase":    "azurerm_app_service_environment",
+	"asr":    "azurerm_recovery_services_vault",
+	"dcr":    "azurerm_monitor_data_collection_rule",
+	"evh":    "azurerm_eventhub_namespace",
+	"kv":     "azurerm_key_vault",
+	"la":     "azurerm_log_analytics_workspace",
+	"las":    "azurerm_log_analytics_solution",
+	"laqp":   "azurerm_log_analytics_query_pack",
+	"nic":    "azurerm_network_interface",
+	"nsg":    "azurerm_network_security_group",
+	"pip":    "azurerm_public_ip",
+	"plan":   "azurerm_app_service_plan",
+	"rg":     "azurerm_resource_group",
+	"snet":   "azurerm_subnet",
+	"sql":    "azurerm_sql_server",
+	"sqldb":  "azurerm_mssql_database",
+	"st":     "azurerm_storage_account",
+	"vml":    "azurerm_linux_virtual_machine",
+	"vmw":    "azurerm_windows_virtual_machine",
+	"vnet":   "azurerm_virtual_network",
+
+	"azurerm_sql_database":                    "azurerm_mssql_database",
+	"azurerm_service_plan":                    "azurerm_app_service_plan",
+	"azurerm_windows_virtual_machine_linux":   "azurerm_linux_virtual_machine",
+	"azurerm_windows_virtual_machine_windows": "azurerm_windows_virtual_machine",
+}
+
+// unmovableResourceTypes are the resource types of azurecaf_naming_convention that
+// have no equivalent in ResourceDefinitions to move to.
+var unmovableResourceTypes = map[string]bool{
+	"aksdns":         true,
+	"aks_dns_prefix": true,
+	"gen":            true,
+	"generic":        true,
+}
+
+// resolveLegacyResourceType returns the ResourceDefinitions key of a resource type
+// of azurecaf_naming_convention.
+func resolveLegacyResourceType(resourceType string) (string, error) {
+	if unmovableResourceTypes[resourceType] {
+		return "", fmt.Errorf("resource type %s has no equivalent in azurecaf_name, its naming rules are only supported by azurecaf_naming_convention", resourceType)
+	}
+	if resourceKey, found := legacyResourceTypes[resourceType]; found {
+		return resourceKey, nil
+	}
+	if _, found := ResourceDefinitions[resourceType]; found {
+		return resourceType, nil
+	}
+	return "", fmt.Errorf("Invalid resource type %s", resourceType)
+}
+
+// getLegacyResource returns the naming rules of a resource type of
+// azurecaf_naming_convention. The short and long codes of the original resource keep
+// their rules and slugs of Resources and ResourcesMapping, so that existing
+// configurations generate the same names. The other types use ResourceDefinitions.
+func getLegacyResource(resourceType string) (ResourceStructure, error) {
+	if resource, found := Resources[resourceType]; found {
+		return resource, nil
+	}
+	if resource, found := ResourcesMapping[resourceType]; found {
+		return resource, nil
+	}
+	if resource, found := ResourceDefinitions[resourceType]; found {
+		return resource, nil
+	}
+	return ResourceStructure{}, fmt.Errorf("Invalid resource type %s", resourceType)
+}
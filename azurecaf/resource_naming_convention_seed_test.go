@@ -0,0 +1,52 @@
+package azurecaf
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCafNamingConvention_RandomSeed(t *testing.T) {
+	golden := []struct {
+		convention   string
+		resourceType string
+		expected     string
+	}{
+		{"cafclassic", "st", "devstmyapp001"},
+		{"cafclassic", "rg", "dev-rg-myapp-001"},
+		{"cafclassic", "kv", "dev-kv-myapp-001"},
+		{"cafclassic", "azurerm_application_gateway", "dev-agw-myapp-001"},
+		{"cafrandom", "st", "devstmyapp001fmsaxuhbdst"},
+		{"cafrandom", "rg", "dev-rg-myapp-001-fmsaxuhbdserpdcdcbkehjo"},
+		{"cafrandom", "kv", "dev-kv-myapp-001-fmsaxut"},
+		{"cafrandom", "azurerm_application_gateway", "dev-agw-myapp-001-fmsaxuhbdserpdcdcbkeho"},
+		{"random", "st", "devfmsaxuhbdserpdcdcbket"},
+		{"random", "rg", "dev-fmsaxuhbdserpdcdcbkehjtatlcthehcpkeo"},
+		{"random", "kv", "dev-fmsaxuhbdserpdcdcbkt"},
+		{"random", "azurerm_application_gateway", "dev-fmsaxuhbdserpdcdcbkehjtatlcthehcpkeo"},
+		{"passthrough", "st", "devmyapp001"},
+		{"passthrough", "rg", "dev-myapp-001"},
+		{"passthrough", "kv", "dev-myapp-001"},
+		{"passthrough", "azurerm_application_gateway", "dev-myapp-001"},
+	}
+
+	for _, tt := range golden {
+		t.Run(tt.convention+"/"+tt.resourceType, func(t *testing.T) {
+			rd := schema.TestResourceDataRaw(t, resourceNamingConvention().Schema, map[string]interface{}{
+				"name":          "myapp",
+				"prefix":        "dev",
+				"postfix":       "001",
+				"resource_type": tt.resourceType,
+				"convention":    tt.convention,
+				"max_length":    40,
+				"random_seed":   42,
+			})
+			if err := getResult(rd, nil); err != nil {
+				t.Fatalf("getResult failed: %v", err)
+			}
+			if result := rd.Get("result").(string); result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -54,3 +56,93 @@ func regexMatch(id string, exp *regexp.Regexp, requiredMatches int) resource.Tes
 		return nil
 	}
 }
+
+func TestLegacyOnlyResourceTypes(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		minLength    int
+		maxLength    int
+		valid        string
+		invalid      []string
+	}{
+		{"gen", 1, 24, "myapp001", []string{"my-app", "my_app", strings.Repeat("a", 25)}},
+		{"generic", 1, 24, "myapp001", []string{"my-app", strings.Repeat("a", 25)}},
+		{"aksdns", 3, 45, "my-cluster-dns", []string{"1cluster", "my_cluster", strings.Repeat("a", 46)}},
+		{"aks_dns_prefix", 3, 45, "my-cluster-dns", []string{"1cluster", "my_cluster", strings.Repeat("a", 46)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			resource, err := getLegacyResource(tt.resourceType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resource.MinLength != tt.minLength || resource.MaxLength != tt.maxLength {
+				t.Errorf("Expected lengths %d-%d, got %d-%d", tt.minLength, tt.maxLength, resource.MinLength, resource.MaxLength)
+			}
+			validationRegEx := regexp.MustCompile(resource.ValidationRegExp)
+			if !validationRegEx.MatchString(tt.valid) {
+				t.Errorf("Expected %s to be valid", tt.valid)
+			}
+			for _, name := range tt.invalid {
+				if validationRegEx.MatchString(name) {
+					t.Errorf("Expected %s to be invalid", name)
+				}
+			}
+
+			// The random convention stays within the legacy rules
+			rd := schema.TestResourceDataRaw(t, resourceNamingConvention().Schema, map[string]interface{}{
+				"name":          "myapp",
+				"resource_type": tt.resourceType,
+				"convention":    ConventionRandom,
+			})
+			if err := getResult(rd, nil); err != nil {
+				t.Fatalf("getResult failed: %v", err)
+			}
+			if result := rd.Get("result").(string); len(result) > tt.maxLength || !validationRegEx.MatchString(result) {
+				t.Errorf("Unexpected result %s", result)
+			}
+
+			// They have no equivalent to move to
+			if _, err := resolveLegacyResourceType(tt.resourceType); err == nil {
+				t.Error("Expected no equivalent in ResourceDefinitions")
+			}
+		})
+	}
+}
+
+func TestLegacyResourceTypes(t *testing.T) {
+	for legacyType, resourceType := range legacyResourceTypes {
+		if _, found := ResourceDefinitions[resourceType]; !found {
+			t.Errorf("%s resolves to %s, which is not a resource definition", legacyType, resourceType)
+		}
+	}
+	// The legacy resource types keep their original naming rules
+	for legacyType, legacyResource := range Resources {
+		resource, err := getLegacyResource(legacyType)
+		if err != nil {
+			t.Errorf("Legacy resource type %s is not resolved: %v", legacyType, err)
+		} else if resource != legacyResource {
+			t.Errorf("Expected %s to keep its naming rules %+v, got %+v", legacyType, legacyResource, resource)
+		}
+	}
+	for legacyType, legacyResource := range ResourcesMapping {
+		resource, err := getLegacyResource(legacyType)
+		if err != nil {
+			t.Errorf("Legacy resource type %s is not resolved: %v", legacyType, err)
+		} else if resource != legacyResource {
+			t.Errorf("Expected %s to keep its naming rules %+v, got %+v", legacyType, legacyResource, resource)
+		}
+	}
+
+	rd := schema.TestResourceDataRaw(t, resourceNamingConvention().Schema, map[string]interface{}{
+		"name":          "automation",
+		"resource_type": "aaa",
+		"convention":    ConventionCafClassic,
+	})
+	if err := getResult(rd, nil); err != nil {
+		t.Fatalf("getResult failed: %v", err)
+	}
+	if result := rd.Get("result").(string); result != "aaa-automation" {
+		t.Errorf("Expected aaa-automation, got %s", result)
+	}
+}
@@ -0,0 +1,114 @@
+// Command azurecaf-codemod replaces hard-coded azurerm resource names with references
+// to azurecaf_name data sources.
+//
+// For every azurerm resource covered by the azurecaf resource definitions whose name
+// is a string literal, a data "azurecaf_name" block reproducing the name is added in
+// front of the resource and the name argument is pointed at its result.
+//
+// Usage:
+//
+//	azurecaf-codemod [-decompose] [-write] [path ...]
+//
+// By default the changes are printed as a unified diff for review. Directories are
+// walked recursively, hidden directories such as .terraform are skipped.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aztfmod/terraform-provider-azurecaf/azurecaf"
+)
+
+func main() {
+	write := flag.Bool("write", false, "rewrite the files in place instead of printing a diff")
+	decompose := flag.Bool("decompose", false, "express names as prefixes, name and suffixes when possible instead of passthrough")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-decompose] [-write] [path ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	os.Exit(run(paths, azurecaf.CodemodOptions{Decompose: *decompose}, *write))
+}
+
+func run(paths []string, options azurecaf.CodemodOptions, write bool) int {
+	files, err := terraformFiles(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	rewrites := 0
+	for _, path := range files {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		result, err := azurecaf.RewriteLiteralNames(path, src, options)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		for _, skipped := range result.Skipped {
+			fmt.Fprintf(os.Stderr, "%s: skipped %s: %s\n", path, skipped.Address, skipped.Reason)
+		}
+		if !result.Changed() {
+			continue
+		}
+		rewrites += len(result.Rewrites)
+
+		if write {
+			// Keep the mode of the rewritten file
+			info, err := os.Stat(path)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 2
+			}
+			if err := os.WriteFile(path, result.Rewritten, info.Mode().Perm()); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 2
+			}
+			continue
+		}
+		fmt.Print(result.Diff())
+	}
+
+	fmt.Fprintf(os.Stderr, "%d resource name(s) rewritten\n", rewrites)
+	return 0
+}
+
+func terraformFiles(paths []string) ([]string, error) {
+	files := []string{}
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if path != root && strings.HasPrefix(entry.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) == ".tf" {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
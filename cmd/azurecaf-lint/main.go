@@ -0,0 +1,58 @@
+// Command azurecaf-lint checks the names of azurerm resources in a Terraform plan
+// against the azurecaf resource definitions before they are applied.
+//
+// Usage:
+//
+//	terraform show -json tfplan > plan.json
+//	azurecaf-lint -format sarif plan.json > naming.sarif
+//
+// The plan is read from standard input when no file is given. The command exits
+// with status 1 when violations are found and 2 when the plan cannot be read.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aztfmod/terraform-provider-azurecaf/azurecaf"
+)
+
+func main() {
+	format := flag.String("format", azurecaf.LintFormatText, "output format: text, json or sarif")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-format text|json|sarif] [plan.json]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	os.Exit(run(flag.Arg(0), *format))
+}
+
+func run(path string, format string) int {
+	var input io.Reader = os.Stdin
+	if path != "" && path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		defer file.Close()
+		input = file
+	}
+
+	findings, err := azurecaf.LintPlan(input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if err := azurecaf.WriteLintFindings(os.Stdout, format, findings); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
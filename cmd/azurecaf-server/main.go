@@ -0,0 +1,35 @@
+// Command azurecaf-server exposes the azurecaf naming rules as a local JSON/HTTP service
+// so that tooling outside of Terraform (portal add-ins, ticketing workflows, scripts)
+// generates the same names as the provider.
+//
+// Usage:
+//
+//	azurecaf-server -listen 127.0.0.1:8080
+//
+// The OpenAPI description of the service is served at /openapi.json.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aztfmod/terraform-provider-azurecaf/azurecaf"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8080", "address the naming service listens on")
+	flag.Parse()
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           azurecaf.NewNamingServer(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+
+	log.Printf("azurecaf naming service listening on %s", *listen)
+	log.Fatal(server.ListenAndServe())
+}
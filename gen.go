@@ -10,6 +10,9 @@
 //   - Naming convention logic
 //   - Resource slug mappings
 //
+// It also reads locationDefinition.json and creates locations_generated.go with the
+// display name, short code, paired region and geography of every Azure region.
+//
 // Usage: go generate (automatically runs this file via go:generate directive in main.go)
 
 //go:build ignore
@@ -27,6 +30,7 @@ import (
 	"path"
 	"regexp"
 	"sort"
+	"strconv"
 	"text/template"
 )
 
@@ -87,18 +91,43 @@ type ResourceStructure struct {
 	Official OfficialData `json:"official"`
 }
 
+// LocationStructure defines the naming attributes of an Azure region as specified in
+// the locationDefinition.json file.
+type LocationStructure struct {
+	// Name is the name of the region (e.g., "westeurope")
+	Name string `json:"name"`
+
+	// DisplayName is the display name of the region (e.g., "West Europe")
+	DisplayName string `json:"display_name"`
+
+	// ShortCode is the standard abbreviation of the region used in names (e.g., "weu")
+	ShortCode string `json:"short_code"`
+
+	// PairedRegion is the name of the paired region, empty when the region has none
+	PairedRegion string `json:"paired_region,omitempty"`
+
+	// Geography is the geography of the region (e.g., "Europe")
+	Geography string `json:"geography"`
+}
+
 // templateData holds the data structure passed to the Go template for code generation
 type templateData struct {
 	ResourceStructures []ResourceStructure // All resource definitions from JSON
 	SlugMap            map[string]string   // Mapping of CAF prefixes to resource types
 }
 
+// locationTemplateData holds the data structure passed to the location template
+type locationTemplateData struct {
+	Locations []LocationStructure // All region definitions from JSON
+}
+
 // main is the entry point for the code generator.
 // It performs the following steps:
 //  1. Reads resource definitions from resourceDefinition.json
 //  2. Loads and parses Go templates from the templates/ directory
 //  3. Processes the resource data to create mappings and deduplicate entries
 //  4. Generates models_generated.go with all resource definitions and validation logic
+//  5. Reads, checks and generates the region definitions of locationDefinition.json
 func main() {
 	// Get the current working directory to locate input files
 	wd, err := os.Getwd()
@@ -153,6 +182,17 @@ func main() {
 		return uniqueData[i].ResourceTypeName < uniqueData[j].ResourceTypeName
 	})
 
+	// Reject the definitions whose regular expressions do not compile, so that the
+	// provider never has to handle them at plan time
+	for _, res := range uniqueData {
+		if err := checkRegexp(res.RegEx); err != nil {
+			log.Fatalf("invalid cleaning regular expression of %s: %s", res.ResourceTypeName, err)
+		}
+		if err := checkRegexp(res.ValidationRegExp); err != nil {
+			log.Fatalf("invalid validation regular expression of %s: %s", res.ResourceTypeName, err)
+		}
+	}
+
 	// Build a mapping of CAF prefixes (slugs) to resource types
 	// This allows reverse lookup from slug to resource type name
 	slugMap := make(map[string]string)
@@ -179,4 +219,67 @@ func main() {
 		log.Fatalf("execution failed: %s", err)
 	}
 	log.Println("File generated")
+
+	generateLocations(wd, parsedTemplate)
+}
+
+// checkRegexp compiles a regular expression of resourceDefinition.json, which holds
+// it as a quoted Go string literal.
+func checkRegexp(literal string) error {
+	pattern, err := strconv.Unquote(literal)
+	if err != nil {
+		return fmt.Errorf("%s is not a quoted string: %w", literal, err)
+	}
+	_, err = regexp.Compile(pattern)
+	return err
+}
+
+// generateLocations generates locations_generated.go from locationDefinition.json.
+// Short codes must be unique and paired regions must be defined.
+func generateLocations(wd string, parsedTemplate *template.Template) {
+	sourceDefinitions, err := os.ReadFile(path.Join(wd, "locationDefinition.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var locations []LocationStructure
+	err = json.Unmarshal(sourceDefinitions, &locations)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Sort by region name for consistent output
+	sort.SliceStable(locations, func(i, j int) bool {
+		return locations[i].Name < locations[j].Name
+	})
+
+	names := make(map[string]bool)
+	shortCodes := make(map[string]string)
+	for _, location := range locations {
+		if names[location.Name] {
+			log.Fatalf("region %s is defined more than once", location.Name)
+		}
+		names[location.Name] = true
+		if other, exists := shortCodes[location.ShortCode]; exists {
+			log.Fatalf("short code %s of %s is already used by %s", location.ShortCode, location.Name, other)
+		}
+		shortCodes[location.ShortCode] = location.Name
+	}
+	for _, location := range locations {
+		if location.PairedRegion != "" && !names[location.PairedRegion] {
+			log.Fatalf("paired region %s of %s is not defined", location.PairedRegion, location.Name)
+		}
+	}
+
+	locationsFile, err := os.OpenFile(path.Join(wd, "azurecaf/locations_generated.go"), os.O_TRUNC|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer locationsFile.Close()
+
+	err = parsedTemplate.ExecuteTemplate(locationsFile, "location.tmpl", locationTemplateData{Locations: locations})
+	if err != nil {
+		log.Fatalf("execution failed: %s", err)
+	}
+	log.Println("Locations generated")
 }
@@ -14,7 +14,6 @@ package main
 
 import (
 	"github.com/aztfmod/terraform-provider-azurecaf/azurecaf"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
 
@@ -25,11 +24,10 @@ import (
 
 // main initializes and serves the Terraform provider using the Terraform plugin SDK.
 // The provider is configured through the azurecaf.Provider() function which defines
-// the available resources and data sources.
+// the available resources and data sources, and served by azurecaf.GRPCProviderServer
+// which adds the state moves of the legacy resources.
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: func() *schema.Provider {
-			return azurecaf.Provider()
-		},
+		GRPCProviderFunc: azurecaf.GRPCProviderServer,
 	})
 }